@@ -21,6 +21,7 @@ import (
 
 	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
 	"github.com/markjaroski/go-mobilizon-bot/mobilizon"
+	"github.com/markjaroski/go-mobilizon-bot/source"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/spf13/pflag"
@@ -29,6 +30,8 @@ import (
 const CC_PLUG = "Help promote your favourite venues with: https://concertcloud.live/contribute"
 const ADDR_FILE = "addrs.json"
 const EVENT_CACHE_FILE = "event_cache.json"
+const MEDIA_CACHE_FILE = "media.json"
+const DEFAULT_CATEGORY = "MUSIC"
 
 // Options represents the full set of command-line options for the bot
 type Options struct {
@@ -37,11 +40,15 @@ type Options struct {
 	Country      *string
 	Limit        *int
 	Page         *int
+	Workers      *int
 	Radius       *int
 	Date         *string
 	File         *string
+	Dir          *string
 	AuthConfig   *string
 	Config       *string
+	Actor        *string
+	Group        *string
 	ActorID      *int
 	GroupID      *int
 	Timezone     *string
@@ -55,11 +62,18 @@ type Options struct {
 	ExistsFile   *string
 	AppName      *string
 	AppURL       *string
+	Job          *string
+	Verify       *bool
 }
 
 type ExistingEvent struct {
 	UUID  uuid.UUID          `json:"uuid"`
 	Event concertcloud.Event `json:"event"`
+	// the job and query scope the event was last seen in
+	Job   string `json:"job,omitempty"`
+	Scope string `json:"scope,omitempty"`
+	// set when the event disappears from its source
+	MissingSince *time.Time `json:"missingSince,omitempty"`
 }
 
 var opts Options
@@ -67,14 +81,17 @@ var opts Options
 // local fields
 var mobClient *mobilizon.Client
 var auth mobilizon.AuthConfig
-var actorID int
-var groupID int
-var timezone *string
+var runConfig *RunConfig
 var addrs map[string]mobilizon.AddressInput
 var existing map[string]ExistingEvent
 var created map[string]ExistingEvent
 var addrsFile string
 var existsFile string
+var optOutFile string
+var mediaFile string
+var webhookClientsFile string
+var mediaCache *mobilizon.MediaCache
+var optOuts *OptOutRegistry
 var authFile string
 var registration *mobilizon.Registration
 
@@ -92,6 +109,36 @@ func init() {
 	created = make(map[string]ExistingEvent)
 }
 
+// defineFlags sets up the command-line flags, defaulting the configuration
+// to the given directory
+func defineFlags(flags *pflag.FlagSet, confdir string) {
+	opts.MobilizonUrl = flags.String("mobilizonurl", "https://mobilisons.ch", "Your Mobilizon base URL")
+	opts.AppName = flags.String("appname", "Concert Cloud", "The name of your client app")
+	opts.AppURL = flags.String("appurl", "https://concertcloud.live", "Your client app's about page")
+	opts.City = flags.String("city", "", "The concertcloud API param 'city'")
+	opts.Country = flags.String("country", "", "The concertcloud API param 'country'")
+	opts.Limit = flags.Int("limit", 10, "The concertcloud API param 'limit'")
+	opts.Page = flags.Int("page", 0, "The concertcloud API param 'page'. Without it every page is fetched.")
+	opts.Workers = flags.Int("workers", 1, "How many concertcloud pages to fetch at once.")
+	opts.Radius = flags.Int("radius", 25, "The concertcloud API param 'radius'")
+	opts.Date = flags.String("date", "", "The concertcloud API param 'date'")
+	opts.File = flags.String("file", "", "Instead of fetching from concertcloud, use local file. Use - to read from stdin.")
+	opts.Dir = flags.String("dir", "", "Instead of fetching from concertcloud, use every goskyr output file in this directory.")
+	opts.Actor = flags.String("actor", "", "The Mobilizon actor ID, or @username, to use as the event organizer.")
+	opts.Group = flags.String("group", "", "The Mobilizon group ID, or @username, to use for the event attribution.")
+	opts.Timezone = flags.String("timezone", "Europe/Zurich", "The timezone to use for the event attribution.")
+	opts.AuthConfig = flags.String("authconfig", confdir+"/mobilizon/auth.json", "Use this file for authorization tokens.")
+	opts.Config = flags.String("config", confdir+"/mobilizon", "Use this directory for configuration.")
+	opts.NoOp = flags.Bool("noop", false, "Gather all required information and report on it, but do not create events in Mobilizòn.")
+	opts.Register = flags.Bool("register", false, "Register this bot and quit. A client id will be output.")
+	opts.Authorize = flags.Bool("authorize", false, "Authorize this bot and quit. An auth token and renew token will be output.")
+	opts.Draft = flags.Bool("draft", false, "Create events in draft mode.")
+	opts.Debug = flags.Bool("debug", false, "Debug mode.")
+	opts.Trace = flags.Bool("trace", false, "Trace mode.")
+	opts.Verify = flags.Bool("verify", false, "Compare cached events with what is live on Mobilizòn and repair drift according to the verify policy.")
+	opts.Job = flags.String("job", "", "Run only the named job from the run configuration (default: all jobs).")
+}
+
 // FIXME: main still does too much of the work
 func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
@@ -109,28 +156,10 @@ func main() {
 		os.Exit(1)
 	}
 
-	opts.MobilizonUrl = pflag.String("mobilizonurl", "https://mobilisons.ch", "Your Mobilizon base URL")
-	opts.AppName = pflag.String("appname", "Concert Cloud", "The name of your client app")
-	opts.AppURL = pflag.String("appurl", "https://concertcloud.live", "Your client app's about page")
-	opts.City = pflag.String("city", "", "The concertcloud API param 'city'")
-	opts.Country = pflag.String("country", "", "The concertcloud API param 'country'")
-	opts.Limit = pflag.Int("limit", 10, "The concertcloud API param 'limit'")
-	opts.Page = pflag.Int("page", 0, "The concertcloud API param 'page'")
-	opts.Radius = pflag.Int("radius", 25, "The concertcloud API param 'radius'")
-	opts.Date = pflag.String("date", "", "The concertcloud API param 'date'")
-	opts.File = pflag.String("file", "", "Instead of fetching from concertcloud, use local file.")
-	opts.ActorID = pflag.Int("actor", -1, "The Mobilizon actor ID to use as the event organizer.")
-	opts.GroupID = pflag.Int("group", -1, "The Mobilizon group ID to use for the event attribution.")
-	opts.Timezone = pflag.String("timezone", "Europe/Zurich", "The timezone to use for the event attribution.")
-	opts.AuthConfig = pflag.String("authconfig", confdir+"/mobilizon/auth.json", "Use this file for authorization tokens.")
-	opts.Config = pflag.String("config", confdir+"/mobilizon", "Use this directory for configuration.")
-	opts.NoOp = pflag.Bool("noop", false, "Gather all required information and report on it, but do not create events in Mobilizòn.")
-	opts.Register = pflag.Bool("register", false, "Register this bot and quit. A client id will be output.")
-	opts.Authorize = pflag.Bool("authorize", false, "Authorize this bot and quit. An auth token and renew token will be output.")
-	opts.Draft = pflag.Bool("draft", false, "Create events in draft mode.")
-	opts.Debug = pflag.Bool("debug", false, "Debug mode.")
-	opts.Trace = pflag.Bool("trace", false, "Trace mode.")
+	defineFlags(pflag.CommandLine, confdir)
 
+	// flags after a subcommand belong to the subcommand
+	pflag.CommandLine.SetInterspersed(false)
 	pflag.Parse()
 
 	if *opts.Debug {
@@ -140,11 +169,18 @@ func main() {
 		Log.SetLevel(hclog.LevelFromString("TRACE"))
 	}
 
+	runConfig, err = loadRunConfig(*opts.Config + "/" + RUN_CONFIG_FILE)
+	if err != nil {
+		Log.Error("error", err)
+		os.Exit(1)
+	}
+	runConfig.applyFlags(pflag.CommandLine)
+
 	if *opts.Register {
 		conf := mobilizon.RegisterConfig{
-			BaseURL: *opts.MobilizonUrl,
-			AppName: *opts.AppName,
-			Website: *opts.AppURL,
+			BaseURL: runConfig.MobilizonUrl,
+			AppName: runConfig.AppName,
+			Website: runConfig.AppURL,
 			Scopes:  mobilizon.DefaultScopes(),
 		}
 		registration, err = mobilizon.RegisterApp(ctx, conf)
@@ -157,78 +193,220 @@ func main() {
 		return
 	}
 
-	if registration == nil {
-		registration, err = mobilizon.LoadRegistration(*opts.Config + "/registration.json")
-		if err != nil {
-			panic("No registration found for application " + *opts.AppName)
-		}
-		*opts.MobilizonUrl = registration.BaseURL
-	}
-
 	if *opts.Config != confdir+"/mobilizon" {
 		*opts.AuthConfig = *opts.Config + "/auth.json"
 	}
 
-	mobClient, err = mobilizon.NewClient(*opts.MobilizonUrl, registration.ClientID)
+	addrsFile = *opts.Config + "/" + ADDR_FILE
+	existsFile = *opts.Config + "/" + EVENT_CACHE_FILE
+	optOutFile = *opts.Config + "/" + OPT_OUT_FILE
+	mediaFile = *opts.Config + "/" + MEDIA_CACHE_FILE
+	webhookClientsFile = *opts.Config + "/" + WEBHOOK_CLIENTS_FILE
+
+	optOuts, err = loadOptOuts(optOutFile)
 	if err != nil {
-		Log.Error("Error creating client", err)
-		panic("Unable to create mobilizon client")
+		Log.Error("error", err)
+		os.Exit(1)
 	}
 
-	// do the authorization
-	if err = mobClient.EnsureAuthorization(ctx, *opts.AuthConfig); err != nil {
+	// usernames given for --actor and --group are looked up before any job
+	// is set up with them
+	if err := resolveIdentityFlags(ctx); err != nil {
+		Log.Error("error", err)
+		os.Exit(1)
+	}
+
+	jobs, err := runConfig.selectJobs(*opts.Job)
+	if err != nil {
+		Log.Error("error", err)
+		os.Exit(1)
+	}
+	if err := applyJobFlags(jobs, pflag.CommandLine); err != nil {
 		Log.Error("error", err)
-		panic(*opts.AppName + " not Authorized")
+		os.Exit(1)
+	}
+
+	// subcommands take over from here and manage their own connection
+	if pflag.NArg() > 0 {
+		if err := runCommand(ctx, pflag.Args()); err != nil {
+			Log.Error("error", err)
+			os.Exit(1)
+		}
+		return
 	}
 
+	connect(ctx)
+
 	// if the user has asked to stop at authorization we're done
 	if *opts.Authorize {
 		return
 	}
 
-	actorID = *opts.ActorID
-	groupID = *opts.GroupID
+	if err := resolveJobIdentities(ctx, jobs); err != nil {
+		Log.Error("error", err)
+		os.Exit(1)
+	}
 
-	addrsFile = *opts.Config + "/" + ADDR_FILE
-	existsFile = *opts.Config + "/" + EVENT_CACHE_FILE
+	// webhook jobs are only run by serve, which checks them itself
+	publishing := slices.DeleteFunc(slices.Clone(jobs), func(j JobConfig) bool { return j.Source == SOURCE_WEBHOOK })
+	if err := preflight(ctx, publishing); err != nil {
+		if !*opts.NoOp {
+			Log.Error("Pre-flight check failed", "error", err)
+			os.Exit(1)
+		}
+		Log.Warn("Pre-flight check failed", "error", err)
+	}
 
-	// this will hold our json object whether local or from ConcertCloud
-	var events []concertcloud.Event
+	if *opts.Verify {
+		verifyEvents(ctx, jobs)
+		return
+	}
 
-	if *opts.File != "" {
-		Log.Info("using local file:", "file", *opts.File)
-		dat, err := os.ReadFile(*opts.File)
+	runJobs(ctx, jobs)
+}
+
+// connect loads the app registration, creates the Mobilizòn client and
+// makes sure it is authorized, unless that is already done
+func connect(ctx context.Context) {
+	if mobClient != nil {
+		return
+	}
+	var err error
+	if registration == nil {
+		registration, err = mobilizon.LoadRegistration(*opts.Config + "/registration.json")
 		if err != nil {
-			Log.Error("error", err)
-			os.Exit(1)
+			panic("No registration found for application " + runConfig.AppName)
 		}
-		// goskyr file output produces a simple json array of Event objects
-		json.Unmarshal(dat, &events)
-	} else {
-		ccConfig := concertcloud.Config{
-			Logger:     Log,
-			HTTPClient: mobClient.HTTPClient(ctx),
+		runConfig.MobilizonUrl = registration.BaseURL
+	}
+
+	mobClient, err = mobilizon.NewClient(runConfig.MobilizonUrl, registration.ClientID)
+	if err != nil {
+		Log.Error("Error creating client", err)
+		panic("Unable to create mobilizon client")
+	}
+	mobClient.SetResilience(resilience())
+	retirer = mobClient
+
+	// do the authorization
+	if err = mobClient.EnsureAuthorization(ctx, *opts.AuthConfig); err != nil {
+		Log.Error("error", err)
+		panic(runConfig.AppName + " not Authorized")
+	}
+
+	// reuse the pictures uploaded in earlier runs
+	if mediaCache, err = mobilizon.LoadMediaCache(mediaFile); err != nil {
+		Log.Error("Error loading media cache", "file", mediaFile, "error", err)
+		mediaCache = mobilizon.NewMediaCache()
+	}
+	mobClient.SetMediaCache(mediaCache)
+}
+
+// fetchEvents gathers the events for a job from its source. For sources
+// made of several feeds it also returns what each feed yielded.
+func fetchEvents(ctx context.Context, job JobConfig) ([]concertcloud.Event, []source.FeedResult, error) {
+	src, err := newSource(ctx, job)
+	if err != nil {
+		return nil, nil, err
+	}
+	Log.Info("Fetching events", "job", job.Name, "source", src)
+	events, err := src.Events(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var feeds []source.FeedResult
+	if r, ok := src.(source.Reporter); ok {
+		feeds = r.Results()
+	}
+	return events, feeds, nil
+}
+
+// newSource builds the source a job reads its events from
+func newSource(ctx context.Context, job JobConfig) (source.Source, error) {
+	switch job.Source {
+	case SOURCE_FILE:
+		return source.NewFile(job.File), nil
+	case SOURCE_DIR:
+		return source.NewDir(job.Dir), nil
+	case SOURCE_STDIN:
+		return source.NewStdin(), nil
+	case SOURCE_ICS:
+		s := source.NewICS(job.URL)
+		s.Venue, s.City, s.Country = job.Venue, job.City, job.Country
+		if job.Horizon > 0 {
+			s.Horizon = job.Horizon
 		}
-		ccClient, err := concertcloud.NewClient(ccConfig)
-		params := concertcloud.QueryParams{
-			City:    *opts.City,
-			Country: *opts.Country,
-			Limit:   *opts.Limit,
-			Page:    *opts.Page,
-			Radius:  *opts.Radius,
-			Date:    *opts.Date,
+		// the timezone was checked with the run configuration
+		s.TZ, _ = time.LoadLocation(job.Timezone)
+		return s, nil
+	case SOURCE_JSONLD:
+		s := source.NewJSONLD(job.Pages...)
+		s.Sitemap = job.Sitemap
+		if job.Match != "" {
+			s.Match = regexp.MustCompile(job.Match)
 		}
-		resp, err := ccClient.GetEvents(ctx, params)
-		// Fetch some concerts from Concert Cloud
+		s.Venue, s.City, s.Country = job.Venue, job.City, job.Country
+		// the timezone was checked with the run configuration
+		s.TZ, _ = time.LoadLocation(job.Timezone)
+		return s, nil
+	case SOURCE_GOSKYR:
+		return source.NewGoskyr(job.Scrapers...), nil
+	case SOURCE_MOBILIZON:
+		near, err := geohash(job.Near)
 		if err != nil {
-			Log.Error("error", err)
+			return nil, err
 		}
+		s := source.NewMobilizon(job.URL, near, float64(job.Radius))
+		remote := mobilizon.NewPublicClient(job.URL)
+		remote.SetResilience(resilience())
+		s.Remote = remote
+		// what is already federated to us is not mirrored again
+		s.Local = mobClient
+		if job.Horizon > 0 {
+			s.Horizon = job.Horizon
+		}
+		return s, nil
+	case SOURCE_CSV:
+		s := source.NewCSV(job.File)
+		s.Columns = job.Columns
+		s.DateFormat = job.DateFormat
+		if job.TimeFormat != "" {
+			s.TimeFormat = job.TimeFormat
+		}
+		if job.Delimiter != "" {
+			s.Delimiter, _ = utf8.DecodeRuneInString(job.Delimiter)
+		}
+		s.Venue, s.City, s.Country = job.Venue, job.City, job.Country
+		// the timezone was checked with the run configuration
+		s.TZ, _ = time.LoadLocation(job.Timezone)
+		return s, nil
+	}
 
-		events = resp.Data
+	ccConfig := concertcloud.Config{
+		Logger:  Log,
+		Workers: job.Workers,
+	}
+	ccClient, err := concertcloud.NewClient(ccConfig)
+	if err != nil {
+		return nil, err
+	}
+	params := concertcloud.QueryParams{
+		City:    job.City,
+		Country: job.Country,
+		Limit:   job.Limit,
+		Page:    job.Page,
+		Radius:  job.Radius,
+		Date:    job.Date,
 	}
+	return source.NewConcertCloud(ccClient, params), nil
+}
 
-	// fetchAddrs(ctx, events)
-	createEvents(ctx, events)
+// resilience is the configured handling of Mobilizòn crashes, logging to
+// our log
+func resilience() mobilizon.Resilience {
+	r := runConfig.Resilience
+	r.Logger = Log
+	return r
 }
 
 func loadAddresses() {
@@ -279,6 +457,13 @@ func saveExistingEvents() {
 	}
 }
 
+func saveMediaCache() {
+	Log.Debug("Saving media cache", "file", mediaFile)
+	if err := mediaCache.Save(mediaFile); err != nil {
+		Log.Error(err.Error())
+	}
+}
+
 // Create a hopefully unique key for a given event.
 //
 // The lineup may change, and many venues change the title to indicate
@@ -299,7 +484,12 @@ func addressToAddressInput(e concertcloud.Event) mobilizon.AddressInput {
 	geo := e.Address.Geolocacation.MongoGeolocation.Coordinates
 	// offset := time.Duration(e.Offset * int(time.Second))
 	// tzName := "UTC" + fmt.Sprintf("%+.0f", offset.Hours())
-	latlong := strconv.FormatFloat(geo[0], 'f', 8, 64) + ";" + strconv.FormatFloat(geo[1], 'f', 8, 64)
+	// not every source knows where the venue is
+	var geom *string
+	if len(geo) >= 2 {
+		latlong := strconv.FormatFloat(geo[0], 'f', 8, 64) + ";" + strconv.FormatFloat(geo[1], 'f', 8, 64)
+		geom = &latlong
+	}
 	street := e.Address.HouseNumber + " " + e.Address.Street
 	nId := "nominatim:" + strconv.FormatInt(e.Address.Geolocacation.OsmID, 10)
 	var originId *string
@@ -309,7 +499,7 @@ func addressToAddressInput(e concertcloud.Event) mobilizon.AddressInput {
 		originId = nil
 	}
 	return mobilizon.AddressInput{
-		Geom:        &latlong,
+		Geom:        geom,
 		Street:      &street,
 		Locality:    &e.Address.Locality,
 		PostalCode:  &e.Address.PostCode,
@@ -323,11 +513,14 @@ func addressToAddressInput(e concertcloud.Event) mobilizon.AddressInput {
 
 // createEvents loops through all of the events in the json input, sets up
 // their variables map, and runs createEvents on them
-func createEvents(ctx context.Context, events []concertcloud.Event) {
+//
+// The event cache must already be loaded; the caller saves it once all jobs
+// have run.
+func createEvents(ctx context.Context, job JobConfig, events []concertcloud.Event) JobSummary {
 
-	Log.Debug("createEvents()", "number of events: ", len(events))
+	Log.Debug("createEvents()", "job", job.Name, "number of events: ", len(events))
 
-	loadExistingEvents()
+	summary := JobSummary{Job: job.Name, Fetched: len(events)}
 
 	for i, e := range events {
 
@@ -337,11 +530,17 @@ func createEvents(ctx context.Context, events []concertcloud.Event) {
 			continue
 		}
 
-		// Do not upload events from bejazz.ch. They don't like us.
-		//
-		// FIXME: this should be loaded from an opt out file or something
-		if match, _ := regexp.MatchString("bejazz.ch", e.URL); match {
-			Log.Info("Skipping BeJazz.")
+		// Do not upload events from venues which have asked us not to
+		if entry := optOuts.Match(e); entry != nil {
+			Log.Info("Skipping opted out event", "kind", entry.Kind, "value", entry.Value, "url", e.URL)
+			summary.Skipped++
+			continue
+		}
+
+		// the job may opt out of further sources by URL pattern
+		if jobOptsOut(job, e) {
+			Log.Info("Skipping opted out event", "job", job.Name, "url", e.URL)
+			summary.Skipped++
 			continue
 		}
 
@@ -353,6 +552,15 @@ func createEvents(ctx context.Context, events []concertcloud.Event) {
 		// Log a warning for missing venues and skip
 		if e.Address.Street == "" {
 			Log.Info("Address not found", "location", e.Location, "city", e.City)
+			summary.Skipped++
+			continue
+		}
+
+		// another job, or an earlier entry of this one, already handled
+		// this event during the current run
+		if _, ok := created[eventKey(e)]; ok {
+			Log.Debug("Duplicate event", "job", job.Name, "eventKey", eventKey(e))
+			summary.Duplicates++
 			continue
 		}
 
@@ -365,28 +573,7 @@ func createEvents(ctx context.Context, events []concertcloud.Event) {
 			e.Title = e.Title + " ..."
 		}
 
-		vars := mobilizon.EventParams{
-			Title:                    e.Title,
-			Description:              e.Comment + " <p/><p> " + CC_PLUG,
-			BeginsOn:                 e.Date,
-			EndsOn:                   e.Date.Add(time.Hour * 2),
-			Category:                 populateCategory(e),
-			Visibility:               mobilizon.EventVisibilityPublic,
-			JoinOptions:              mobilizon.EventJoinOptionsExternal,
-			PhysicalAddress:          addressToAddressInput(e),
-			OnlineAddress:            e.URL,
-			ExternalParticipationURL: e.URL,
-			Draft:                    *opts.Draft,
-			OrganizerActorId:         actorID,
-			AttributedToId:           groupID,
-			Tags:                     populateTags(e),
-			Options:                  populateEventOptions(),
-			Status:                   mobilizon.EventStatusConfirmed,
-		}
-
-		if e.ImageURL != "" {
-			vars.ImageURL = e.ImageURL
-		}
+		vars := eventParams(job, e)
 
 		var existingUuid = &uuid.UUID{}
 
@@ -398,7 +585,7 @@ func createEvents(ctx context.Context, events []concertcloud.Event) {
 			Log.Debug("Found a cached event", "key", eventKey(e))
 			Log.Trace("Found a cached event", "event", spew.Sdump(existing[eventKey(e)].UUID))
 			*existingUuid = existing[eventKey(e)].UUID
-			created[eventKey(e)] = existing[eventKey(e)]
+			created[eventKey(e)] = job.cacheEntry(existing[eventKey(e)].UUID, existing[eventKey(e)].Event)
 
 		} else {
 			Log.Debug("Searching for existing events", "title", e.Title, "location", e.Location, "date", e.Date)
@@ -410,22 +597,12 @@ func createEvents(ctx context.Context, events []concertcloud.Event) {
 				e.Date,
 			)
 
-			if err != nil && err.Error() == "returned error 401: {\"data\":null}" {
-				mobClient.RefreshToken(ctx, *opts.AuthConfig)
-				Log.Info("Authorization Token Refreshed")
-				exists, uuid, err = mobClient.EventExists(
-					ctx,
-					e.Title,
-					e.Location,
-					e.City,
-					e.Date,
-				)
-			} else if err != nil {
+			if err != nil {
 				Log.Error("Error searching for a matching event", "error", err)
 			}
 
 			if exists {
-				created[eventKey(e)] = ExistingEvent{*uuid, e}
+				created[eventKey(e)] = job.cacheEntry(*uuid, e)
 				existingUuid = uuid
 			}
 		}
@@ -440,20 +617,45 @@ func createEvents(ctx context.Context, events []concertcloud.Event) {
 			if !reflect.DeepEqual(e, existing[eventKey(e)].Event) {
 				Log.Debug("Update", "uuid", existingUuid)
 				Log.Trace("Update", "saved", spew.Sdump(existing[eventKey(e)].Event), "event", spew.Sdump(e))
-				vars.UUID = existingUuid
-				if _, err := mobClient.UpdateEvent(ctx, vars); err != nil {
+				var err error
+				if cached, ok := existing[eventKey(e)]; ok {
+					// only send the fields which changed
+					patch, changes := diffEvents(job, *existingUuid, cached.Event, e)
+					if len(changes) == 0 {
+						Log.Debug("No mirrored field changed", "eventKey", eventKey(e))
+						created[eventKey(e)] = job.cacheEntry(*existingUuid, e)
+						summary.Unchanged++
+						continue
+					}
+					Log.Info("Changes", "eventKey", eventKey(e), "changelog", changelog(changes))
+					if clearsTags(patch) {
+						vars.UUID = existingUuid
+						_, err = mobClient.UpdateEvent(ctx, vars)
+					} else {
+						_, err = mobClient.PatchEvent(ctx, patch)
+					}
+				} else {
+					// the event was found by searching, so we don't know
+					// what it looks like and send all of it
+					vars.UUID = existingUuid
+					_, err = mobClient.UpdateEvent(ctx, vars)
+				}
+				if err != nil {
 					Log.Error("Error updating event", "error", err)
 					// it could be a transient error, cache the cached version
 					// again so that we try to update again next time
-					created[eventKey(e)] = existing[eventKey(e)]
+					created[eventKey(e)] = job.cacheEntry(*existingUuid, existing[eventKey(e)].Event)
+					summary.Failed++
 				} else {
 					// cache the updated event
-					created[eventKey(e)] = ExistingEvent{*existingUuid, e}
+					created[eventKey(e)] = job.cacheEntry(*existingUuid, e)
 					Log.Info("Updated", "eventKey", eventKey(e), "index", i)
+					summary.Updated++
 				}
 				continue
 			} else {
 				// the event hasn't changed, there's nothing to do
+				summary.Unchanged++
 				continue
 			}
 		}
@@ -462,30 +664,73 @@ func createEvents(ctx context.Context, events []concertcloud.Event) {
 
 		uuid, err := mobClient.CreateEvent(ctx, vars)
 		if err == nil {
-			created[eventKey(e)] = ExistingEvent{*uuid, e}
+			created[eventKey(e)] = job.cacheEntry(*uuid, e)
 			Log.Info("Created", "eventKey", eventKey(e), "index", i)
+			summary.Created++
 		} else {
 			Log.Error("Error creating event", "error", err)
+			summary.Failed++
 		}
 		time.Sleep(30000)
 	}
-	Log.Debug("Saving existing events list")
-	Log.Trace("Saving existing events list", "events", spew.Sdump(created))
-	saveExistingEvents()
+	return summary
+}
+
+// jobOptsOut reports whether the event URL matches one of the job's
+// opt-out patterns
+func jobOptsOut(job JobConfig, e concertcloud.Event) bool {
+	for _, pattern := range job.OptOut {
+		if match, _ := regexp.MatchString(pattern, e.URL); match {
+			return true
+		}
+	}
+	return false
 }
 
-// populateTags constructs an eventTags object for the createEvent mutation
+// eventParams builds the Mobilizòn event the job publishes for a source
+// event
+func eventParams(job JobConfig, e concertcloud.Event) mobilizon.EventParams {
+	vars := mobilizon.EventParams{
+		Title:                    e.Title,
+		Description:              e.Comment + " <p/><p> " + CC_PLUG,
+		BeginsOn:                 e.Date,
+		EndsOn:                   e.Date.Add(time.Hour * 2),
+		Category:                 populateCategory(e, job.Category),
+		Visibility:               mobilizon.EventVisibilityPublic,
+		JoinOptions:              mobilizon.EventJoinOptionsExternal,
+		PhysicalAddress:          addressToAddressInput(e),
+		OnlineAddress:            e.URL,
+		ExternalParticipationURL: e.URL,
+		Draft:                    job.Draft,
+		OrganizerActorId:         job.ActorID,
+		AttributedToId:           job.GroupID,
+		Tags:                     populateTags(e),
+		Options:                  populateEventOptions(job.Timezone),
+		Status:                   mobilizon.EventStatusConfirmed,
+	}
+
+	if e.ImageURL != "" {
+		vars.ImageURL = e.ImageURL
+	}
+
+	return vars
+}
+
+// populateTags constructs an eventTags object for the createEvent mutation,
+// leaving out blank tags
 func populateTags(e concertcloud.Event) []*string {
-	return []*string{
-		&e.Location,
-		&e.City,
+	tags := []*string{}
+	for _, t := range []string{e.Location, e.City} {
+		if strings.TrimSpace(t) != "" {
+			tags = append(tags, &t)
+		}
 	}
+	return tags
 }
 
 // populateEventOptions creates a default eventOptionsInput object
 // FIXME should od this in init()
-func populateEventOptions() mobilizon.EventOptionsInput {
-	tz := *opts.Timezone
+func populateEventOptions(tz string) mobilizon.EventOptionsInput {
 	showStart := true
 	showEnd := false
 	moderation := mobilizon.EventCommentModeration("ALLOW_ALL")
@@ -499,11 +744,11 @@ func populateEventOptions() mobilizon.EventOptionsInput {
 
 // populateCategory takes an event and returns either the event's own
 // category if it is found in the list of Mobilizòn's event categories or
-// the default category
-// FIXME refactor this as an Event object method. Make the default a constant.
-func populateCategory(e concertcloud.Event) mobilizon.EventCategory {
+// the job's default category
+// FIXME refactor this as an Event object method.
+func populateCategory(e concertcloud.Event, def string) mobilizon.EventCategory {
 	if slices.Contains(mobilizon.AllEventCategory, mobilizon.EventCategory(e.Type)) {
 		return mobilizon.EventCategory(e.Type)
 	}
-	return mobilizon.EventCategory("MUSIC")
+	return mobilizon.EventCategory(def)
 }
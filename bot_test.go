@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/markjaroski/go-mobilizon-bot/mobilizon"
+)
+
+// graphQLCall is an operation the fake Mobilizòn server was sent
+type graphQLCall struct {
+	Operation string
+	Vars      map[string]any
+}
+
+// fakeMobilizon points mobClient at a server answering GraphQL operations
+// by name. Each handler receives the request variables and returns the
+// JSON body to send back. The calls made are returned once the test asks
+// for them.
+func fakeMobilizon(t *testing.T, handlers map[string]func(vars map[string]any) string) func() []graphQLCall {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []graphQLCall
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			OperationName string         `json:"operationName"`
+			Variables     map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid GraphQL request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		calls = append(calls, graphQLCall{req.OperationName, req.Variables})
+		mu.Unlock()
+		handler, ok := handlers[req.OperationName]
+		if !ok {
+			t.Errorf("unexpected operation %q", req.OperationName)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(handler(req.Variables)))
+	}))
+	t.Cleanup(server.Close)
+
+	client := mobilizon.NewPublicClient(server.URL)
+	saved := mobClient
+	mobClient = client
+	t.Cleanup(func() { mobClient = saved })
+
+	return func() []graphQLCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(calls)
+	}
+}
+
+// useNoOp sets --noop for the test
+func useNoOp(t *testing.T, noop bool) {
+	t.Helper()
+	saved := opts.NoOp
+	opts.NoOp = &noop
+	t.Cleanup(func() { opts.NoOp = saved })
+}
+
+// useTimezone sets --timezone for the test
+func useTimezone(t *testing.T, tz string) {
+	t.Helper()
+	saved := opts.Timezone
+	opts.Timezone = &tz
+	t.Cleanup(func() { opts.Timezone = saved })
+}
+
+// useCache replaces the event cache for the test, with created empty
+func useCache(t *testing.T, events map[string]ExistingEvent) {
+	t.Helper()
+	savedExisting, savedCreated := existing, created
+	existing = maps.Clone(events)
+	if existing == nil {
+		existing = make(map[string]ExistingEvent)
+	}
+	created = make(map[string]ExistingEvent)
+	t.Cleanup(func() { existing, created = savedExisting, savedCreated })
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/pflag"
+
+	"github.com/markjaroski/go-mobilizon-bot/mobilizon"
+)
+
+// what to do with events already mirrored from an opted-out venue
+const MIRRORED_ASK = "ask"
+const MIRRORED_DELETE = "delete"
+const MIRRORED_CANCEL = "cancel"
+const MIRRORED_KEEP = "keep"
+
+// runCommand dispatches the subcommand given after the global flags
+func runCommand(ctx context.Context, args []string) error {
+	switch args[0] {
+	case "optout":
+		return optOutCommand(ctx, args[1:])
+	case "media":
+		return mediaCommand(ctx, args[1:])
+	case "serve":
+		return serveCommand(ctx, args[1:])
+	case "clients":
+		return clientsCommand(args[1:])
+	case "export":
+		return exportCommand(args[1:])
+	case "group-events":
+		return groupEventsCommand(ctx, args[1:])
+	case "whoami", "identities":
+		return whoamiCommand(ctx)
+	}
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+// optOutCommand manages the opt-out registry:
+//
+//	optout list
+//	optout add <kind> <value> [--reason text] [--mirrored ask|delete|cancel|keep]
+//	optout remove <kind> <value>
+func optOutCommand(ctx context.Context, args []string) error {
+	flags := pflag.NewFlagSet("optout", pflag.ContinueOnError)
+	reason := flags.String("reason", "", "Why the venue opted out.")
+	mirrored := flags.String("mirrored", MIRRORED_ASK, "What to do with events already mirrored: ask, delete, cancel or keep.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	switch flags.Arg(0) {
+	case "list", "":
+		for _, o := range optOuts.Entries {
+			fmt.Printf("%-8s %-40s %s %s\n", o.Kind, o.Value, o.Date.Format(time.DateOnly), o.Reason)
+		}
+		return nil
+
+	case "add":
+		if flags.NArg() != 3 {
+			return errors.New("usage: optout add <kind> <value> [--reason text]")
+		}
+		entry := OptOutEntry{
+			Kind:   flags.Arg(1),
+			Value:  flags.Arg(2),
+			Reason: *reason,
+			Date:   time.Now(),
+		}
+		if err := optOuts.Add(entry); err != nil {
+			return err
+		}
+		if err := optOuts.save(optOutFile); err != nil {
+			return err
+		}
+		Log.Info("Opted out", "kind", entry.Kind, "value", entry.Value)
+		return retireMirroredEvents(ctx, &optOuts.Entries[len(optOuts.Entries)-1], *mirrored)
+
+	case "remove":
+		if flags.NArg() != 3 {
+			return errors.New("usage: optout remove <kind> <value>")
+		}
+		if !optOuts.Remove(flags.Arg(1), flags.Arg(2)) {
+			return fmt.Errorf("%s %q is not opted out", flags.Arg(1), flags.Arg(2))
+		}
+		Log.Info("Removed opt-out", "kind", flags.Arg(1), "value", flags.Arg(2))
+		return optOuts.save(optOutFile)
+	}
+	return fmt.Errorf("unknown optout command %q", flags.Arg(0))
+}
+
+// mediaCommand manages the pictures we have uploaded:
+//
+//	media list
+//	media cleanup
+//
+// cleanup removes the pictures no mirrored event uses any more from
+// Mobilizòn. With --noop it only lists them.
+func mediaCommand(ctx context.Context, args []string) error {
+	switch {
+	case len(args) == 0 || args[0] == "list":
+		cache, err := mobilizon.LoadMediaCache(mediaFile)
+		if err != nil {
+			return err
+		}
+		for _, m := range cache.Entries() {
+			fmt.Printf("%s %s %3d events  %s\n", m.UUID, m.Uploaded.Format(time.DateOnly), len(m.Events), m.Hash[:12])
+		}
+		return nil
+
+	case args[0] == "cleanup":
+		connect(ctx)
+		orphans, err := mobClient.OrphanedMedia(ctx)
+		if err != nil {
+			return err
+		}
+		removed := 0
+		for _, m := range orphans {
+			if *opts.NoOp || m.ID == "" {
+				Log.Info("Orphaned media", "uuid", m.UUID, "uploaded", m.Uploaded)
+				continue
+			}
+			if err := mobClient.RemoveMedia(ctx, m.ID); err != nil {
+				Log.Error("Error removing media", "uuid", m.UUID, "error", err)
+				continue
+			}
+			Log.Info("Removed media", "uuid", m.UUID)
+			mediaCache.Remove(m.Hash)
+			removed++
+		}
+		Log.Info("Media cleanup", "orphaned", len(orphans), "removed", removed)
+		saveMediaCache()
+		return nil
+	}
+	return fmt.Errorf("unknown media command %q", args[0])
+}
+
+// serveCommand runs the webhook server venues push their events to:
+//
+//	serve [--listen :8080]
+func serveCommand(ctx context.Context, args []string) error {
+	flags := pflag.NewFlagSet("serve", pflag.ContinueOnError)
+	listen := flags.String("listen", ":8080", "The address to listen on.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	clients, err := loadWebhookClients(webhookClientsFile)
+	if err != nil {
+		return err
+	}
+	return serve(ctx, *listen, clients)
+}
+
+// clientsCommand manages the API keys of the webhook clients:
+//
+//	clients list
+//	clients add <name> <job>
+//	clients remove <name>
+//
+// The key of a new client is printed once and only its hash is kept.
+func clientsCommand(args []string) error {
+	clients, err := loadWebhookClients(webhookClientsFile)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(args) == 0 || args[0] == "list":
+		for _, c := range clients.Clients {
+			fmt.Printf("%-20s %-20s %s\n", c.Name, c.Job, c.Created.Format(time.DateOnly))
+		}
+		return nil
+
+	case args[0] == "add":
+		if len(args) != 3 {
+			return errors.New("usage: clients add <name> <job>")
+		}
+		if !slices.ContainsFunc(runConfig.Jobs, func(j JobConfig) bool { return j.Name == args[2] }) {
+			return fmt.Errorf("job %q not found", args[2])
+		}
+		key, err := clients.Add(args[1], args[2])
+		if err != nil {
+			return err
+		}
+		if err := clients.save(webhookClientsFile); err != nil {
+			return err
+		}
+		fmt.Printf("API key for %s, shown only once:\n%s\n", args[1], key)
+		return nil
+
+	case args[0] == "remove":
+		if len(args) != 2 {
+			return errors.New("usage: clients remove <name>")
+		}
+		if !clients.Remove(args[1]) {
+			return fmt.Errorf("webhook client %q not found", args[1])
+		}
+		Log.Info("Removed webhook client", "name", args[1])
+		return clients.save(webhookClientsFile)
+	}
+	return fmt.Errorf("unknown clients command %q", args[0])
+}
+
+// exportCommand writes the future events we have published as iCalendar,
+// RSS and Atom feeds, one per job, city or venue:
+//
+//	export [--by job|city|venue] [--format ics,rss,atom] [--dir path]
+func exportCommand(args []string) error {
+	flags := pflag.NewFlagSet("export", pflag.ContinueOnError)
+	by := flags.String("by", EXPORT_BY_JOB, "Write one feed per job, city or venue.")
+	formats := flags.StringSlice("format", []string{"ics", "rss", "atom"}, "The feed formats to write.")
+	dir := flags.String("dir", ".", "The directory to write the feeds to.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		return err
+	}
+
+	loadExistingEvents()
+	exportBaseURL()
+	files, err := exportFeeds(existing, *by, *formats, *dir, time.Now())
+	for _, f := range files {
+		Log.Info("Exported", "file", f)
+	}
+	return err
+}
+
+// groupEventsCommand writes the upcoming events of our Mobilizòn group
+// which the bot did not create as event-api JSON, ready to be submitted to
+// ConcertCloud:
+//
+//	group-events [--job name] [--out file]
+//
+// The group is the one of the job, or the one given with --group.
+func groupEventsCommand(ctx context.Context, args []string) error {
+	flags := pflag.NewFlagSet("group-events", pflag.ContinueOnError)
+	job := flags.String("job", "", "The job whose group to list.")
+	out := flags.String("out", "", "The file to write to, instead of stdout.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	groupID := *opts.GroupID
+	if *job != "" {
+		i := slices.IndexFunc(runConfig.Jobs, func(j JobConfig) bool { return j.Name == *job })
+		if i < 0 {
+			return fmt.Errorf("job %q not found", *job)
+		}
+		if err := resolveJobIdentities(ctx, runConfig.Jobs[i:i+1]); err != nil {
+			return err
+		}
+		groupID = runConfig.Jobs[i].GroupID
+	}
+	if groupID <= 0 {
+		return errors.New("no group given, use --group or --job")
+	}
+
+	connect(ctx)
+	loadExistingEvents()
+	found, err := mobClient.GroupEvents(ctx, strconv.Itoa(groupID), time.Now())
+	if err != nil {
+		return err
+	}
+	events := groupEvents(found, existing, runConfig.MobilizonUrl)
+	Log.Info("Group events", "group", groupID, "found", len(found), "exported", len(events))
+
+	data, err := json.MarshalIndent(events, "", " ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if *out == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*out, data, 0644)
+}
+
+// whoamiCommand lists the profiles the bot may publish as and the groups
+// they are members of, with the IDs to use for --actor and --group:
+//
+//	whoami
+func whoamiCommand(ctx context.Context) error {
+	connect(ctx)
+	identities, err := mobClient.Identities(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range identities {
+		fmt.Printf("%-8s %-40s %s\n", id.ID, id.Username(), id.Name)
+		for _, m := range id.Memberships {
+			fmt.Printf("  group %-8s %-34s %-14s %s\n", m.Group.ID, m.Group.Username(), m.Role, m.Group.Name)
+		}
+	}
+	return nil
+}
+
+// retireMirroredEvents looks for events we have already mirrored which
+// match a new opt-out entry, and deletes or cancels them on Mobilizòn
+func retireMirroredEvents(ctx context.Context, entry *OptOutEntry, action string) error {
+	loadExistingEvents()
+
+	var keys []string
+	for k, ev := range existing {
+		if entry.matches(ev.Event) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+
+	fmt.Printf("%d mirrored events match %s %q:\n", len(keys), entry.Kind, entry.Value)
+	for _, k := range keys {
+		fmt.Printf("  %s  %s\n", existing[k].UUID, k)
+	}
+
+	if action == MIRRORED_ASK {
+		action = askMirroredAction()
+	}
+
+	switch action {
+	case MIRRORED_KEEP:
+		return nil
+	case MIRRORED_DELETE, MIRRORED_CANCEL:
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
+
+	connect(ctx)
+
+	for _, k := range keys {
+		if err := retireEvent(ctx, existing[k].UUID, action); err != nil {
+			Log.Error("Error retiring event", "action", action, "eventKey", k, "error", err)
+			continue
+		}
+		Log.Info("Retired", "action", action, "eventKey", k)
+		delete(existing, k)
+	}
+
+	// nothing else was touched, so the cache is saved as it was loaded
+	created = existing
+	saveExistingEvents()
+	return nil
+}
+
+// eventRetirer takes events down on Mobilizòn
+type eventRetirer interface {
+	DeleteEvent(ctx context.Context, id uuid.UUID) error
+	CancelEvent(ctx context.Context, id uuid.UUID) error
+}
+
+// retirer deletes or cancels mirrored events, connect sets it to the
+// Mobilizòn client
+var retirer eventRetirer
+
+// retireEvent deletes or cancels a mirrored event on Mobilizòn
+func retireEvent(ctx context.Context, id uuid.UUID, action string) error {
+	switch action {
+	case MIRRORED_DELETE:
+		return retirer.DeleteEvent(ctx, id)
+	case MIRRORED_CANCEL:
+		return retirer.CancelEvent(ctx, id)
+	}
+	return fmt.Errorf("unknown action %q", action)
+}
+
+// askMirroredAction prompts on the terminal for what to do with events
+// that are already mirrored
+func askMirroredAction() string {
+	fmt.Print("[d]elete, [c]ancel or [k]eep them? ")
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "d", "delete":
+		return MIRRORED_DELETE
+	case "c", "cancel":
+		return MIRRORED_CANCEL
+	}
+	return MIRRORED_KEEP
+}
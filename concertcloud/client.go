@@ -20,6 +20,7 @@ type Client struct {
 	baseURL    string
 	httpClient *http.Client
 	logger     hclog.Logger
+	workers    int
 }
 
 // Config holds client configuration
@@ -27,6 +28,8 @@ type Config struct {
 	BaseURL    string
 	Logger     hclog.Logger
 	HTTPClient *http.Client
+	// Workers is how many pages AllEvents fetches at once (default 1)
+	Workers int
 }
 
 // NewClient creates a new ConcertCloud client
@@ -37,11 +40,15 @@ func NewClient(config Config) (*Client, error) {
 	if config.HTTPClient == nil {
 		config.HTTPClient = http.DefaultClient
 	}
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
 
 	return &Client{
 		baseURL:    config.BaseURL,
 		httpClient: config.HTTPClient,
 		logger:     config.Logger,
+		workers:    config.Workers,
 	}, nil
 }
 
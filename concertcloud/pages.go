@@ -0,0 +1,121 @@
+package concertcloud
+
+import (
+	"context"
+	"iter"
+)
+
+// pageResult is one fetched page, or the error fetching it
+type pageResult struct {
+	resp *EventResponse
+	err  error
+}
+
+// AllEvents returns an iterator over the events of every page of a query,
+// from params.Page (or the first page) up to the last page the API
+// reports. With more than one worker the pages after the first are
+// fetched concurrently, but the events are still yielded in page order.
+//
+// Iteration stops at the first error, which is yielded with an empty
+// event, and when the context is cancelled.
+func (c *Client) AllEvents(ctx context.Context, params QueryParams) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		if params.Page < 1 {
+			params.Page = 1
+		}
+
+		// the first page tells us how many there are
+		first, err := c.GetEvents(ctx, params)
+		if err != nil {
+			yield(Event{}, err)
+			return
+		}
+		if !yieldPage(ctx, first, yield) {
+			return
+		}
+		if first.LastPage <= params.Page || len(first.Data) == 0 {
+			return
+		}
+
+		for res := range c.fetchPages(ctx, params, params.Page+1, first.LastPage) {
+			if res.err != nil {
+				yield(Event{}, res.err)
+				return
+			}
+			if !yieldPage(ctx, res.resp, yield) {
+				return
+			}
+		}
+	}
+}
+
+// yieldPage passes on the events of a page and reports whether to go on
+func yieldPage(ctx context.Context, resp *EventResponse, yield func(Event, error) bool) bool {
+	for _, e := range resp.Data {
+		if !yield(e, nil) {
+			return false
+		}
+	}
+	if ctx.Err() != nil {
+		yield(Event{}, ctx.Err())
+		return false
+	}
+	return true
+}
+
+// fetchPages fetches the pages from first to last with at most c.workers
+// requests in flight, and sends the results in page order. The channel is
+// closed after the last page, the first error or when the context is
+// cancelled.
+func (c *Client) fetchPages(ctx context.Context, params QueryParams, first int, last int) <-chan pageResult {
+	out := make(chan pageResult)
+
+	// one buffered channel per page keeps the results in order
+	pages := make([]chan pageResult, last-first+1)
+	for i := range pages {
+		pages[i] = make(chan pageResult, 1)
+	}
+
+	sem := make(chan struct{}, c.workers)
+	go func() {
+		for i := range pages {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(i int) {
+				defer func() { <-sem }()
+				p := params
+				p.Page = first + i
+				resp, err := c.GetEvents(ctx, p)
+				pages[i] <- pageResult{resp, err}
+			}(i)
+		}
+	}()
+
+	go func() {
+		defer close(out)
+		for _, page := range pages {
+			var res pageResult
+			select {
+			case res = <-page:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case out <- res:
+			case <-ctx.Done():
+				return
+			}
+			if res.err != nil {
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,188 @@
+package concertcloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// pagedServer serves lastPage pages of perPage events each, titled
+// "page-event", and fails the page given as failPage
+func pagedServer(t *testing.T, lastPage, perPage, failPage int, delay time.Duration) (*httptest.Server, *int32, *int32) {
+	t.Helper()
+	var requests, inFlight, maxInFlight int32
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		mu.Lock()
+		if n > maxInFlight {
+			maxInFlight = n
+		}
+		mu.Unlock()
+		time.Sleep(delay)
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == failPage {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("boom"))
+			return
+		}
+		events := make([]Event, perPage)
+		for i := range events {
+			events[i] = Event{Title: fmt.Sprintf("%d-%d", page, i)}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(EventResponse{
+			Data:     events,
+			Page:     page,
+			Limit:    perPage,
+			Total:    lastPage * perPage,
+			LastPage: lastPage,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests, &maxInFlight
+}
+
+// TestAllEvents tests following the pages of a query
+func TestAllEvents(t *testing.T) {
+	tests := []struct {
+		name       string
+		lastPage   int
+		startPage  int
+		workers    int
+		failPage   int
+		wantEvents int
+		wantFirst  string
+		wantErr    bool
+	}{
+		{name: "single page", lastPage: 1, workers: 1, wantEvents: 3, wantFirst: "1-0"},
+		{name: "all pages", lastPage: 5, workers: 1, wantEvents: 15, wantFirst: "1-0"},
+		{name: "from a later page", lastPage: 5, startPage: 4, workers: 1, wantEvents: 6, wantFirst: "4-0"},
+		{name: "concurrent", lastPage: 8, workers: 3, wantEvents: 24, wantFirst: "1-0"},
+		{name: "error on a page", lastPage: 5, workers: 1, failPage: 3, wantEvents: 6, wantFirst: "1-0", wantErr: true},
+		{name: "concurrent error", lastPage: 5, workers: 4, failPage: 3, wantEvents: 6, wantFirst: "1-0", wantErr: true},
+		{name: "error on the first page", lastPage: 5, workers: 1, failPage: 1, wantEvents: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _, _ := pagedServer(t, tt.lastPage, 3, tt.failPage, 0)
+			client, _ := NewClient(Config{BaseURL: server.URL, Workers: tt.workers})
+
+			var titles []string
+			var gotErr error
+			for e, err := range client.AllEvents(context.Background(), QueryParams{City: "Test", Page: tt.startPage, Limit: 3}) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				titles = append(titles, e.Title)
+			}
+
+			if (gotErr != nil) != tt.wantErr {
+				t.Fatalf("AllEvents() error = %v, wantErr %v", gotErr, tt.wantErr)
+			}
+			if len(titles) != tt.wantEvents {
+				t.Fatalf("Expected %d events, got %d: %v", tt.wantEvents, len(titles), titles)
+			}
+			if tt.wantEvents > 0 && titles[0] != tt.wantFirst {
+				t.Errorf("Expected first event %s, got %s", tt.wantFirst, titles[0])
+			}
+			// pages come in order even when fetched concurrently
+			for i := 1; i < len(titles); i++ {
+				var pa, ia, pb, ib int
+				fmt.Sscanf(titles[i-1], "%d-%d", &pa, &ia)
+				fmt.Sscanf(titles[i], "%d-%d", &pb, &ib)
+				if pb < pa || (pb == pa && ib <= ia) {
+					t.Fatalf("Events out of order: %v", titles)
+				}
+			}
+		})
+	}
+}
+
+// TestAllEvents_BoundedWorkers tests that no more than the configured
+// number of pages are fetched at once
+func TestAllEvents_BoundedWorkers(t *testing.T) {
+	server, requests, maxInFlight := pagedServer(t, 10, 2, 0, 20*time.Millisecond)
+	client, _ := NewClient(Config{BaseURL: server.URL, Workers: 3})
+
+	count := 0
+	for _, err := range client.AllEvents(context.Background(), QueryParams{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		count++
+	}
+
+	if count != 20 {
+		t.Errorf("Expected 20 events, got %d", count)
+	}
+	if *requests != 10 {
+		t.Errorf("Expected 10 requests, got %d", *requests)
+	}
+	if *maxInFlight > 3 {
+		t.Errorf("Expected at most 3 requests at once, got %d", *maxInFlight)
+	}
+	if *maxInFlight < 2 {
+		t.Errorf("Expected pages to be fetched concurrently, got %d at once", *maxInFlight)
+	}
+}
+
+// TestAllEvents_StopEarly tests that breaking out of the loop stops
+// fetching pages
+func TestAllEvents_StopEarly(t *testing.T) {
+	server, requests, _ := pagedServer(t, 50, 2, 0, 0)
+	client, _ := NewClient(Config{BaseURL: server.URL})
+
+	for e := range client.AllEvents(context.Background(), QueryParams{Limit: 2}) {
+		if e.Title == "2-1" {
+			break
+		}
+	}
+
+	// the next page may already be on its way
+	if *requests > 3 {
+		t.Errorf("Expected at most 3 requests, got %d", *requests)
+	}
+}
+
+// TestAllEvents_ContextCancelled tests that cancelling the context ends
+// the iteration with the context error
+func TestAllEvents_ContextCancelled(t *testing.T) {
+	server, _, _ := pagedServer(t, 50, 2, 0, 5*time.Millisecond)
+	client, _ := NewClient(Config{BaseURL: server.URL, Workers: 4})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	count := 0
+	var gotErr error
+	for _, err := range client.AllEvents(ctx, QueryParams{Limit: 2}) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		count++
+		if count == 3 {
+			cancel()
+		}
+	}
+
+	if gotErr == nil {
+		t.Fatal("Expected an error after cancelling")
+	}
+	if count >= 100 {
+		t.Errorf("Expected iteration to stop early, got %d events", count)
+	}
+}
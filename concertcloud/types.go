@@ -2,6 +2,7 @@ package concertcloud
 
 import (
 	"context"
+	"iter"
 
 	"github.com/jakopako/event-api/models"
 )
@@ -11,6 +12,7 @@ type EventFetcher interface {
 	GetEvents(ctx context.Context, params QueryParams) (*EventResponse, error)
 	GetEventsByCity(ctx context.Context, city string, limit int) (*EventResponse, error)
 	GetEventsByCountry(ctx context.Context, country string, limit int) (*EventResponse, error)
+	AllEvents(ctx context.Context, params QueryParams) iter.Seq2[Event, error]
 }
 
 // Event is an alias for models.Event from event-api
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+	"github.com/markjaroski/go-mobilizon-bot/mobilizon"
+	"github.com/markjaroski/go-mobilizon-bot/source"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const RUN_CONFIG_FILE = "bot.yml"
+
+// source names understood by newSource
+const SOURCE_CONCERTCLOUD = "concertcloud"
+const SOURCE_FILE = "file"
+const SOURCE_DIR = "dir"
+const SOURCE_STDIN = "stdin"
+const SOURCE_ICS = "ics"
+const SOURCE_JSONLD = "jsonld"
+const SOURCE_GOSKYR = "goskyr"
+const SOURCE_CSV = "csv"
+const SOURCE_MOBILIZON = "mobilizon"
+const SOURCE_WEBHOOK = "webhook"
+
+// the job built from flags when none are configured
+const DEFAULT_JOB = "default"
+
+// RunConfig is the declarative run configuration loaded from the config
+// directory. Command-line flags only override what is set here.
+type RunConfig struct {
+	MobilizonUrl string       `yaml:"mobilizonurl"`
+	AppName      string       `yaml:"appname"`
+	AppURL       string       `yaml:"appurl"`
+	Jobs         []JobConfig  `yaml:"jobs"`
+	Verify       VerifyPolicy `yaml:"verify"`
+	// Resilience sets how requests to Mobilizòn ride out server crashes
+	Resilience mobilizon.Resilience `yaml:"resilience"`
+}
+
+// who wins when a field on Mobilizòn differs from the source
+const POLICY_BOT = "bot"
+const POLICY_HUMAN = "human"
+
+// VerifyPolicy decides, per field, whether --verify repairs a divergence by
+// sending the bot's value again or leaves the human edit in place
+type VerifyPolicy struct {
+	Title   string `yaml:"title"`
+	Dates   string `yaml:"dates"`
+	Address string `yaml:"address"`
+	Picture string `yaml:"picture"`
+	Tags    string `yaml:"tags"`
+	Status  string `yaml:"status"`
+}
+
+// defaultVerifyPolicy lets moderators keep their titles and status changes
+func defaultVerifyPolicy() VerifyPolicy {
+	return VerifyPolicy{
+		Title:   POLICY_HUMAN,
+		Dates:   POLICY_BOT,
+		Address: POLICY_BOT,
+		Picture: POLICY_BOT,
+		Tags:    POLICY_BOT,
+		Status:  POLICY_HUMAN,
+	}
+}
+
+// JobConfig describes one named feed: where the events come from and how
+// they are attributed on Mobilizòn
+type JobConfig struct {
+	Name     string   `yaml:"name"`
+	Source   string   `yaml:"source"`
+	File     string   `yaml:"file"`
+	Dir      string   `yaml:"dir"`
+	URL      string   `yaml:"url"`
+	Venue    string   `yaml:"venue"`
+	Pages    []string `yaml:"pages"`
+	Sitemap  string   `yaml:"sitemap"`
+	Match    string   `yaml:"match"`
+	Scrapers []string `yaml:"scrapers"`
+	City     string   `yaml:"city"`
+	Country  string   `yaml:"country"`
+	Limit    int      `yaml:"limit"`
+	Page     int      `yaml:"page"`
+	Workers  int      `yaml:"workers"`
+	Radius   int      `yaml:"radius"`
+	Date     string   `yaml:"date"`
+	// Actor and Group are IDs, or usernames which resolveJobIdentities
+	// turns into ActorID and GroupID
+	Actor    string   `yaml:"actor"`
+	Group    string   `yaml:"group"`
+	ActorID  int      `yaml:"-"`
+	GroupID  int      `yaml:"-"`
+	Timezone string   `yaml:"timezone"`
+	Draft    bool     `yaml:"draft"`
+	Category string   `yaml:"category"`
+	OptOut   []string `yaml:"optout"`
+	// what to do with future events which disappear from the source, and
+	// how long to wait before doing it
+	Vanished      string        `yaml:"vanished"`
+	VanishedGrace time.Duration `yaml:"vanished_grace"`
+	// how far ahead recurring events of a calendar feed are expanded
+	Horizon time.Duration `yaml:"horizon"`
+	// the CSV column of each event field, and how its dates are written
+	Columns    map[string]string `yaml:"columns"`
+	DateFormat string            `yaml:"date_format"`
+	TimeFormat string            `yaml:"time_format"`
+	Delimiter  string            `yaml:"delimiter"`
+	// the centre of the area searched on another Mobilizòn instance, as
+	// "lat,lon" or a geohash
+	Near string `yaml:"near"`
+}
+
+// defaultJob returns a job populated with the flag defaults
+func defaultJob(name string) JobConfig {
+	return JobConfig{
+		Name:          name,
+		Source:        SOURCE_CONCERTCLOUD,
+		Limit:         10,
+		Radius:        25,
+		Workers:       1,
+		ActorID:       -1,
+		GroupID:       -1,
+		Timezone:      "Europe/Zurich",
+		Category:      DEFAULT_CATEGORY,
+		Vanished:      MIRRORED_KEEP,
+		VanishedGrace: 24 * time.Hour,
+	}
+}
+
+// UnmarshalYAML fills in the defaults for any field the job leaves out
+func (j *JobConfig) UnmarshalYAML(node *yaml.Node) error {
+	type plain JobConfig
+	p := plain(defaultJob(""))
+	if err := node.Decode(&p); err != nil {
+		return err
+	}
+	if id, err := strconv.Atoi(p.Actor); err == nil {
+		p.ActorID = id
+	}
+	if id, err := strconv.Atoi(p.Group); err == nil {
+		p.GroupID = id
+	}
+	*j = JobConfig(p)
+	return nil
+}
+
+// loadRunConfig reads the run configuration from the given file. A missing
+// file is not an error: the bot then runs a single job built from flags.
+func loadRunConfig(path string) (*RunConfig, error) {
+	rc := RunConfig{Verify: defaultVerifyPolicy(), Resilience: mobilizon.DefaultResilience()}
+	dat, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &rc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(dat, &rc); err != nil {
+		return nil, fmt.Errorf("invalid run configuration %s: %w", path, err)
+	}
+	if err := rc.validate(); err != nil {
+		return nil, fmt.Errorf("invalid run configuration %s: %w", path, err)
+	}
+	return &rc, nil
+}
+
+// validate checks that job names are unique and sources are known
+func (rc *RunConfig) validate() error {
+	for _, p := range []string{rc.Verify.Title, rc.Verify.Dates, rc.Verify.Address, rc.Verify.Picture, rc.Verify.Tags, rc.Verify.Status} {
+		if p != POLICY_BOT && p != POLICY_HUMAN {
+			return fmt.Errorf("unknown verify policy %q, expected %q or %q", p, POLICY_BOT, POLICY_HUMAN)
+		}
+	}
+	r := rc.Resilience
+	if r.RetryMax < 0 || r.RetryWaitMin < 0 || r.RetryWaitMax < r.RetryWaitMin {
+		return fmt.Errorf("invalid resilience retries: retry_max and retry_wait_min must not be negative, nor retry_wait_max below retry_wait_min")
+	}
+	if r.BreakerThreshold < 1 || r.BreakerCooldown < 0 || r.ProbeInterval <= 0 || r.ProbeTimeout <= 0 || r.MaxPause < 0 {
+		return fmt.Errorf("invalid resilience breaker: breaker_threshold, probe_interval and probe_timeout must be positive, breaker_cooldown and max_pause not negative")
+	}
+	names := make(map[string]bool)
+	for i, j := range rc.Jobs {
+		if j.Name == "" {
+			return fmt.Errorf("job %d has no name", i)
+		}
+		if names[j.Name] {
+			return fmt.Errorf("duplicate job name %q", j.Name)
+		}
+		names[j.Name] = true
+		if !slices.Contains(mobilizon.AllEventCategory, mobilizon.EventCategory(j.Category)) {
+			return fmt.Errorf("job %q: unknown category %q", j.Name, j.Category)
+		}
+		if _, err := time.LoadLocation(j.Timezone); err != nil {
+			return fmt.Errorf("job %q: invalid timezone: %w", j.Name, err)
+		}
+		switch j.Vanished {
+		case MIRRORED_KEEP, MIRRORED_CANCEL, MIRRORED_DELETE:
+		default:
+			return fmt.Errorf("job %q: unknown vanished action %q", j.Name, j.Vanished)
+		}
+		switch j.Source {
+		case SOURCE_CONCERTCLOUD, SOURCE_STDIN, SOURCE_WEBHOOK:
+		case SOURCE_DIR:
+			if j.Dir == "" {
+				return fmt.Errorf("job %q: source %q requires a dir", j.Name, j.Source)
+			}
+		case SOURCE_ICS:
+			if j.URL == "" {
+				return fmt.Errorf("job %q: source %q requires a url", j.Name, j.Source)
+			}
+		case SOURCE_JSONLD:
+			if len(j.Pages) == 0 && j.Sitemap == "" {
+				return fmt.Errorf("job %q: source %q requires pages or a sitemap", j.Name, j.Source)
+			}
+			if _, err := regexp.Compile(j.Match); err != nil {
+				return fmt.Errorf("job %q: invalid match: %w", j.Name, err)
+			}
+		case SOURCE_GOSKYR:
+			if len(j.Scrapers) == 0 {
+				return fmt.Errorf("job %q: source %q requires scrapers", j.Name, j.Source)
+			}
+		case SOURCE_FILE:
+			if j.File == "" {
+				return fmt.Errorf("job %q: source %q requires a file", j.Name, j.Source)
+			}
+		case SOURCE_MOBILIZON:
+			if j.URL == "" {
+				return fmt.Errorf("job %q: source %q requires a url", j.Name, j.Source)
+			}
+			if _, err := geohash(j.Near); err != nil {
+				return fmt.Errorf("job %q: invalid near: %w", j.Name, err)
+			}
+		case SOURCE_CSV:
+			if j.File == "" {
+				return fmt.Errorf("job %q: source %q requires a file", j.Name, j.Source)
+			}
+			for field := range j.Columns {
+				if !slices.Contains(source.CSVFields, field) {
+					return fmt.Errorf("job %q: unknown CSV field %q", j.Name, field)
+				}
+			}
+			if utf8.RuneCountInString(j.Delimiter) > 1 {
+				return fmt.Errorf("job %q: delimiter %q is not a single character", j.Name, j.Delimiter)
+			}
+		default:
+			return fmt.Errorf("job %q: unknown source %q", j.Name, j.Source)
+		}
+	}
+	return nil
+}
+
+// applyFlags overrides the top-level settings with any flag that was
+// explicitly set on the command line
+func (rc *RunConfig) applyFlags(flags *pflag.FlagSet) {
+	if flags.Changed("mobilizonurl") || rc.MobilizonUrl == "" {
+		rc.MobilizonUrl = *opts.MobilizonUrl
+	}
+	if flags.Changed("appname") || rc.AppName == "" {
+		rc.AppName = *opts.AppName
+	}
+	if flags.Changed("appurl") || rc.AppURL == "" {
+		rc.AppURL = *opts.AppURL
+	}
+}
+
+// selectJobs returns the named job from the configuration, every configured
+// job if no name is given, or a job built purely from flags if none are
+// configured
+func (rc *RunConfig) selectJobs(name string) ([]JobConfig, error) {
+	if len(rc.Jobs) == 0 {
+		if name != "" {
+			return nil, fmt.Errorf("job %q not found: no jobs configured", name)
+		}
+		return []JobConfig{defaultJob(DEFAULT_JOB)}, nil
+	}
+	if name == "" {
+		return slices.Clone(rc.Jobs), nil
+	}
+	for _, j := range rc.Jobs {
+		if j.Name == name {
+			return []JobConfig{j}, nil
+		}
+	}
+	return nil, fmt.Errorf("job %q not found", name)
+}
+
+// singleJobFlags change where a job's events come from or whom they are
+// attributed to, which is only ever meant for one job
+var singleJobFlags = []string{"file", "dir", "actor", "group", "city", "country"}
+
+// applyJobFlags overrides the selected jobs with the flags given. The flags
+// in singleJobFlags are refused unless exactly one job is selected, so that
+// they don't turn every configured job into the same one. An unknown
+// --timezone is refused, as the timezones in bot.yml are by validate.
+func applyJobFlags(jobs []JobConfig, flags *pflag.FlagSet) error {
+	if _, err := time.LoadLocation(*opts.Timezone); err != nil {
+		return fmt.Errorf("--timezone: %w", err)
+	}
+	if len(jobs) != 1 {
+		for _, name := range singleJobFlags {
+			if flags.Changed(name) {
+				return fmt.Errorf("--%s would apply to all %d jobs, select one with --job", name, len(jobs))
+			}
+		}
+	}
+	for i := range jobs {
+		jobs[i].applyFlags(flags)
+	}
+	return nil
+}
+
+// scope identifies the query a job runs, so that events are only reconciled
+// against the source they came from
+func (j JobConfig) scope() string {
+	switch j.Source {
+	case SOURCE_FILE:
+		return SOURCE_FILE + ":" + j.File
+	case SOURCE_DIR:
+		return SOURCE_DIR + ":" + j.Dir
+	case SOURCE_STDIN:
+		return SOURCE_STDIN
+	case SOURCE_WEBHOOK:
+		return SOURCE_WEBHOOK + ":" + j.Name
+	case SOURCE_ICS:
+		return SOURCE_ICS + ":" + j.URL
+	case SOURCE_JSONLD:
+		return SOURCE_JSONLD + ":" + j.Sitemap + "," + strings.Join(j.Pages, ",")
+	case SOURCE_GOSKYR:
+		return SOURCE_GOSKYR + ":" + strings.Join(j.Scrapers, ",")
+	case SOURCE_CSV:
+		return SOURCE_CSV + ":" + j.File
+	case SOURCE_MOBILIZON:
+		return fmt.Sprintf("%s:%s,near=%s,radius=%d", SOURCE_MOBILIZON, j.URL, j.Near, j.Radius)
+	}
+	return fmt.Sprintf("%s:city=%s,country=%s,radius=%d,date=%s,page=%d",
+		j.Source, j.City, j.Country, j.Radius, j.Date, j.Page)
+}
+
+// geohash reads a position given as "lat,lon" or as a geohash
+func geohash(near string) (string, error) {
+	near = strings.TrimSpace(near)
+	lat, lon, found := strings.Cut(near, ",")
+	if !found {
+		if near == "" || strings.Trim(strings.ToLower(near), "0123456789bcdefghjkmnpqrstuvwxyz") != "" {
+			return "", fmt.Errorf("%q is neither lat,lon nor a geohash", near)
+		}
+		return strings.ToLower(near), nil
+	}
+	y, errLat := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	x, errLon := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if errLat != nil || errLon != nil || y < -90 || y > 90 || x < -180 || x > 180 {
+		return "", fmt.Errorf("%q is not a valid lat,lon", near)
+	}
+	return mobilizon.Geohash(y, x, 6), nil
+}
+
+// cacheEntry builds the event cache entry for an event seen by this job
+func (j JobConfig) cacheEntry(id uuid.UUID, e concertcloud.Event) ExistingEvent {
+	return ExistingEvent{
+		UUID:  id,
+		Event: e,
+		Job:   j.Name,
+		Scope: j.scope(),
+	}
+}
+
+// applyFlags overrides the job's settings with any flag that was explicitly
+// set on the command line
+func (j *JobConfig) applyFlags(flags *pflag.FlagSet) {
+	if flags.Changed("file") {
+		j.File = *opts.File
+		j.Source = SOURCE_FILE
+		if j.File == "-" {
+			j.Source = SOURCE_STDIN
+		}
+	}
+	if flags.Changed("dir") {
+		j.Dir = *opts.Dir
+		j.Source = SOURCE_DIR
+	}
+	if flags.Changed("city") {
+		j.City = *opts.City
+	}
+	if flags.Changed("country") {
+		j.Country = *opts.Country
+	}
+	if flags.Changed("limit") {
+		j.Limit = *opts.Limit
+	}
+	if flags.Changed("page") {
+		j.Page = *opts.Page
+	}
+	if flags.Changed("workers") {
+		j.Workers = *opts.Workers
+	}
+	if flags.Changed("radius") {
+		j.Radius = *opts.Radius
+	}
+	if flags.Changed("date") {
+		j.Date = *opts.Date
+	}
+	if flags.Changed("actor") {
+		j.Actor, j.ActorID = "", *opts.ActorID
+	}
+	if flags.Changed("group") {
+		j.Group, j.GroupID = "", *opts.GroupID
+	}
+	if flags.Changed("timezone") {
+		j.Timezone = *opts.Timezone
+	}
+	if flags.Changed("draft") {
+		j.Draft = *opts.Draft
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+// parseFlags defines the flags on a fresh set and parses args, resolving
+// --actor and --group the way numeric IDs are
+func parseFlags(t *testing.T, args ...string) *pflag.FlagSet {
+	t.Helper()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	defineFlags(flags, t.TempDir())
+	if err := flags.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	actorID, err := resolveIdentity(t.Context(), *opts.Actor, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groupID, err := resolveIdentity(t.Context(), *opts.Group, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.ActorID, opts.GroupID = &actorID, &groupID
+	return flags
+}
+
+func TestLoadRunConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), RUN_CONFIG_FILE)
+	conf := `
+mobilizonurl: https://mobilisons.ch
+jobs:
+  - name: switzerland
+    country: Switzerland
+    limit: 2000
+    actor: 65691
+    group: 73091
+  - name: polesud
+    source: file
+    file: polesud.json
+    draft: true
+  - name: venue
+    actor: "@venue@mobilisons.ch"
+    group: "@concerts"
+`
+	if err := os.WriteFile(path, []byte(conf), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := loadRunConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.MobilizonUrl != "https://mobilisons.ch" || len(rc.Jobs) != 3 {
+		t.Fatalf("expected the instance and three jobs, got %+v", rc)
+	}
+
+	// what a job leaves out comes from the flag defaults
+	ch := rc.Jobs[0]
+	if ch.Source != SOURCE_CONCERTCLOUD || ch.Limit != 2000 || ch.Radius != 25 || ch.Timezone != "Europe/Zurich" {
+		t.Errorf("expected the defaults around the configured values, got %+v", ch)
+	}
+	if ch.ActorID != 65691 || ch.GroupID != 73091 {
+		t.Errorf("expected actor 65691 and group 73091, got %d and %d", ch.ActorID, ch.GroupID)
+	}
+	ps := rc.Jobs[1]
+	if ps.Source != SOURCE_FILE || ps.File != "polesud.json" || !ps.Draft || ps.ActorID != -1 {
+		t.Errorf("expected a draft file job without an actor, got %+v", ps)
+	}
+	// usernames are resolved once connected
+	venue := rc.Jobs[2]
+	if venue.Actor != "@venue@mobilisons.ch" || venue.Group != "@concerts" || venue.ActorID != -1 || venue.GroupID != -1 {
+		t.Errorf("expected the usernames to be kept for resolving, got %+v", venue)
+	}
+}
+
+func TestLoadRunConfig_Missing(t *testing.T) {
+	// without a configuration file the bot runs from flags alone
+	rc, err := loadRunConfig(filepath.Join(t.TempDir(), RUN_CONFIG_FILE))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rc.Jobs) != 0 {
+		t.Errorf("expected no jobs, got %+v", rc.Jobs)
+	}
+}
+
+func TestLoadRunConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    string
+		wantErr string
+	}{
+		{"unnamed job", "jobs:\n  - country: Switzerland\n", "no name"},
+		{"duplicate name", "jobs:\n  - name: ch\n  - name: ch\n", "duplicate"},
+		{"unknown source", "jobs:\n  - name: ch\n    source: ftp\n", "unknown source"},
+		{"file source without a file", "jobs:\n  - name: ch\n    source: file\n", "requires a file"},
+		{"misspelt timezone", "jobs:\n  - name: ch\n    timezone: Europe/Zürich\n", "invalid timezone"},
+		{"not yaml", "jobs: [", "invalid run configuration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), RUN_CONFIG_FILE)
+			if err := os.WriteFile(path, []byte(tt.conf), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := loadRunConfig(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error about %s, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSelectJobs(t *testing.T) {
+	rc := &RunConfig{Jobs: []JobConfig{defaultJob("zurich"), defaultJob("bern")}}
+	jobs, err := rc.selectJobs("")
+	if err != nil || len(jobs) != 2 {
+		t.Fatalf("expected every job, got %v, %v", jobs, err)
+	}
+	// the selected jobs are a copy the flags may change
+	jobs[0].Draft = true
+	if rc.Jobs[0].Draft {
+		t.Error("expected the configuration itself to be left alone")
+	}
+	if jobs, err := rc.selectJobs("bern"); err != nil || len(jobs) != 1 || jobs[0].Name != "bern" {
+		t.Errorf("expected only bern, got %v, %v", jobs, err)
+	}
+	if _, err := rc.selectJobs("basel"); err == nil {
+		t.Error("expected an error for an unknown job")
+	}
+	if jobs, err := (&RunConfig{}).selectJobs(""); err != nil || len(jobs) != 1 || jobs[0].Name != "default" {
+		t.Errorf("expected the default job without configured jobs, got %v, %v", jobs, err)
+	}
+	if _, err := (&RunConfig{}).selectJobs("bern"); err == nil {
+		t.Error("expected an error without configured jobs")
+	}
+}
+
+func TestApplyJobFlags(t *testing.T) {
+	zurich := defaultJob("zurich")
+	zurich.City, zurich.GroupID = "Zürich", 1
+	bern := defaultJob("bern")
+	bern.City, bern.GroupID = "Bern", 2
+	rc := &RunConfig{Jobs: []JobConfig{zurich, bern}}
+
+	tests := []struct {
+		name    string
+		job     string
+		args    []string
+		wantErr string
+		check   func(t *testing.T, jobs []JobConfig)
+	}{
+		{
+			name: "settings apply to every job",
+			args: []string{"--draft", "--limit=50"},
+			check: func(t *testing.T, jobs []JobConfig) {
+				for _, j := range jobs {
+					if !j.Draft || j.Limit != 50 {
+						t.Errorf("job %s: expected draft and limit 50, got %v and %d", j.Name, j.Draft, j.Limit)
+					}
+				}
+			},
+		},
+		{name: "group refused for all jobs", args: []string{"--actor=65691", "--group=73091"}, wantErr: "--actor"},
+		{name: "file refused for all jobs", args: []string{"--file=events.json"}, wantErr: "--file"},
+		{name: "dir refused for all jobs", args: []string{"--dir=out"}, wantErr: "--dir"},
+		{name: "city refused for all jobs", args: []string{"--city=Basel"}, wantErr: "--city"},
+		{name: "country refused for all jobs", args: []string{"--country=Germany"}, wantErr: "--country"},
+		{name: "misspelt timezone refused", job: "bern", args: []string{"--timezone=Europe/Bärn"}, wantErr: "--timezone"},
+		{
+			name: "attribution applies to the selected job",
+			job:  "bern",
+			args: []string{"--actor=65691", "--group=73091", "--city=Thun"},
+			check: func(t *testing.T, jobs []JobConfig) {
+				if len(jobs) != 1 || jobs[0].Name != "bern" {
+					t.Fatalf("expected only bern, got %v", jobs)
+				}
+				if jobs[0].ActorID != 65691 || jobs[0].GroupID != 73091 || jobs[0].City != "Thun" {
+					t.Errorf("expected the overrides on bern, got %+v", jobs[0])
+				}
+			},
+		},
+		{
+			name: "file turns the selected job into a file source",
+			job:  "zurich",
+			args: []string{"--file=-"},
+			check: func(t *testing.T, jobs []JobConfig) {
+				if jobs[0].Source != SOURCE_STDIN {
+					t.Errorf("expected source %q, got %q", SOURCE_STDIN, jobs[0].Source)
+				}
+			},
+		},
+		{
+			name: "unchanged jobs keep their configuration",
+			check: func(t *testing.T, jobs []JobConfig) {
+				if jobs[0].GroupID != 1 || jobs[1].GroupID != 2 || jobs[1].City != "Bern" {
+					t.Errorf("expected the configured groups and cities, got %+v", jobs)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := parseFlags(t, tt.args...)
+			jobs, err := rc.selectJobs(tt.job)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = applyJobFlags(jobs, flags)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error about %s, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, jobs)
+		})
+	}
+	if rc.Jobs[0].Draft || rc.Jobs[1].ActorID != -1 {
+		t.Error("expected the configuration itself to be left alone")
+	}
+}
+
+func TestApplyJobFlags_NoJobsConfigured(t *testing.T) {
+	// without a configuration the flags describe the single default job
+	flags := parseFlags(t, "--city=Basel", "--group=73091")
+	jobs, err := (&RunConfig{}).selectJobs("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := applyJobFlags(jobs, flags); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if jobs[0].City != "Basel" || jobs[0].GroupID != 73091 {
+		t.Errorf("expected the flags on the default job, got %+v", jobs[0])
+	}
+}
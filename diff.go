@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+	"github.com/markjaroski/go-mobilizon-bot/mobilizon"
+)
+
+// Change is one Mobilizòn field which differs between the cached and the
+// new version of a source event
+type Change struct {
+	Field string
+	Old   string
+	New   string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %q -> %q", c.Field, c.Old, c.New)
+}
+
+// diffEvents compares what the job would send to Mobilizòn for the cached
+// and the new version of a source event. It returns a patch holding only
+// the changed fields and a changelog describing them. Changes to source
+// fields we don't mirror produce an empty changelog.
+func diffEvents(job JobConfig, id uuid.UUID, old concertcloud.Event, e concertcloud.Event) (mobilizon.EventPatch, []Change) {
+	was := eventParams(job, old)
+	now := eventParams(job, e)
+
+	patch := mobilizon.EventPatch{UUID: id}
+	var changes []Change
+
+	if was.Title != now.Title {
+		patch.Title = &now.Title
+		changes = append(changes, Change{"title", was.Title, now.Title})
+	}
+	if was.Description != now.Description {
+		patch.Description = &now.Description
+		changes = append(changes, Change{"description", was.Description, now.Description})
+	}
+	if !was.BeginsOn.Equal(now.BeginsOn) || !was.EndsOn.Equal(now.EndsOn) {
+		patch.BeginsOn = &now.BeginsOn
+		patch.EndsOn = &now.EndsOn
+		changes = append(changes, Change{"dates", was.BeginsOn.Format(time.RFC3339), now.BeginsOn.Format(time.RFC3339)})
+	}
+	if !reflect.DeepEqual(was.PhysicalAddress, now.PhysicalAddress) {
+		patch.PhysicalAddress = &now.PhysicalAddress
+		changes = append(changes, Change{"address", wantAddress(was.PhysicalAddress), wantAddress(now.PhysicalAddress)})
+	}
+	// the picture on Mobilizòn is kept when the source drops its image
+	if was.ImageURL != now.ImageURL && now.ImageURL != "" {
+		patch.ImageURL = &now.ImageURL
+		changes = append(changes, Change{"image", was.ImageURL, now.ImageURL})
+	}
+	if was.Category != now.Category {
+		patch.Category = &now.Category
+		changes = append(changes, Change{"category", string(was.Category), string(now.Category)})
+	}
+	if w, n := wantTags(was.Tags), wantTags(now.Tags); !slices.Equal(w, n) {
+		patch.Tags = now.Tags
+		changes = append(changes, Change{"tags", strings.Join(w, ", "), strings.Join(n, ", ")})
+	}
+	if was.OnlineAddress != now.OnlineAddress {
+		patch.OnlineAddress = &now.OnlineAddress
+		patch.ExternalParticipationURL = &now.ExternalParticipationURL
+		changes = append(changes, Change{"url", was.OnlineAddress, now.OnlineAddress})
+	}
+
+	return patch, changes
+}
+
+// clearsTags reports whether the patch removes every tag. A patch leaves
+// out an empty tag list like an unchanged one, so clearing the tags takes
+// a full update.
+func clearsTags(patch mobilizon.EventPatch) bool {
+	return patch.Tags != nil && len(patch.Tags) == 0
+}
+
+// changelog renders the changes on one line for the log
+func changelog(changes []Change) string {
+	lines := make([]string, len(changes))
+	for i, c := range changes {
+		lines[i] = c.String()
+	}
+	return strings.Join(lines, "; ")
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+	"github.com/markjaroski/go-mobilizon-bot/mobilizon"
+)
+
+// patchFields lists the fields a patch sets
+func patchFields(p mobilizon.EventPatch) []string {
+	var fields []string
+	set := func(field string, ok bool) {
+		if ok {
+			fields = append(fields, field)
+		}
+	}
+	set("title", p.Title != nil)
+	set("description", p.Description != nil)
+	set("beginsOn", p.BeginsOn != nil)
+	set("endsOn", p.EndsOn != nil)
+	set("category", p.Category != nil)
+	set("tags", p.Tags != nil)
+	set("address", p.PhysicalAddress != nil)
+	set("onlineAddress", p.OnlineAddress != nil)
+	set("externalParticipationUrl", p.ExternalParticipationURL != nil)
+	set("image", p.ImageURL != nil)
+	return fields
+}
+
+func TestDiffEvents(t *testing.T) {
+	old := concertcloud.Event{
+		Title:    "Erika Stucky",
+		Comment:  "Yodel and jazz",
+		City:     "Bern",
+		Location: "Dampfzentrale",
+		Country:  "Switzerland",
+		Date:     time.Date(2031, 5, 1, 19, 0, 0, 0, time.UTC),
+		URL:      "https://dampfzentrale.ch/erika-stucky",
+		ImageURL: "https://dampfzentrale.ch/erika.jpg",
+		Type:     string(mobilizon.EventCategoryMusic),
+		Genres:   []string{"jazz"},
+	}
+	old.Address.Street = "Marzilistrasse"
+	old.Address.Locality = "Bern"
+	old.Address.Geolocacation.Coordinates = []float64{7.4436, 46.9426}
+
+	tests := []struct {
+		name    string
+		edit    func(e *concertcloud.Event)
+		fields  []string
+		changes []string
+	}{
+		{name: "unchanged"},
+		{
+			name: "fields we don't mirror",
+			edit: func(e *concertcloud.Event) {
+				e.Country = "Schweiz"
+				e.Offset = 7200
+				e.SourceURL = "https://dampfzentrale.ch/programm"
+				e.Genres = []string{"yodel"}
+				e.GenresText = "yodel"
+			},
+		},
+		{name: "title", edit: func(e *concertcloud.Event) { e.Title = "Erika Stucky & Band" }, fields: []string{"title"}, changes: []string{"title"}},
+		{name: "description", edit: func(e *concertcloud.Event) { e.Comment = "Sold out" }, fields: []string{"description"}, changes: []string{"description"}},
+		{name: "dates", edit: func(e *concertcloud.Event) { e.Date = e.Date.Add(30 * time.Minute) }, fields: []string{"beginsOn", "endsOn"}, changes: []string{"dates"}},
+		{name: "address", edit: func(e *concertcloud.Event) { e.Address.Street = "Marzilistr." }, fields: []string{"address"}, changes: []string{"address"}},
+		{name: "image", edit: func(e *concertcloud.Event) { e.ImageURL = "https://dampfzentrale.ch/erika-2.jpg" }, fields: []string{"image"}, changes: []string{"image"}},
+		{name: "image cleared", edit: func(e *concertcloud.Event) { e.ImageURL = "" }},
+		{name: "category", edit: func(e *concertcloud.Event) { e.Type = string(mobilizon.EventCategoryTheatre) }, fields: []string{"category"}, changes: []string{"category"}},
+		{name: "unknown category falling back to the same", edit: func(e *concertcloud.Event) { e.Type = "concert" }},
+		{name: "tags", edit: func(e *concertcloud.Event) { e.City = "Bümpliz" }, fields: []string{"tags"}, changes: []string{"tags"}},
+		{name: "tags differing in case", edit: func(e *concertcloud.Event) { e.City = "BERN" }},
+		{name: "url", edit: func(e *concertcloud.Event) { e.URL = "https://dampfzentrale.ch/en/erika-stucky" }, fields: []string{"onlineAddress", "externalParticipationUrl"}, changes: []string{"url"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := old
+			e.Genres = append([]string(nil), old.Genres...)
+			if tt.edit != nil {
+				tt.edit(&e)
+			}
+			id := uuid.New()
+			patch, changes := diffEvents(defaultJob("bern"), id, old, e)
+			if patch.UUID != id {
+				t.Errorf("expected the patch for %s, got %s", id, patch.UUID)
+			}
+			if got := patchFields(patch); !reflect.DeepEqual(got, tt.fields) {
+				t.Errorf("expected patch fields %v, got %v", tt.fields, got)
+			}
+			var fields []string
+			for _, c := range changes {
+				fields = append(fields, c.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.changes) {
+				t.Errorf("expected changes %v, got %v", tt.changes, fields)
+			}
+		})
+	}
+}
+
+func TestDiffEvents_TagOrder(t *testing.T) {
+	// swapping venue and city reorders the tags, which is no change to
+	// them, but moves the address
+	old := concertcloud.Event{City: "Bern", Location: "Dampfzentrale", Date: time.Date(2031, 5, 1, 19, 0, 0, 0, time.UTC)}
+	old.Address.Geolocacation.Coordinates = []float64{7.4436, 46.9426}
+	e := old
+	e.City, e.Location = old.Location, old.City
+	patch, _ := diffEvents(defaultJob("bern"), uuid.New(), old, e)
+	if got := patchFields(patch); !reflect.DeepEqual(got, []string{"address"}) {
+		t.Errorf("expected only the address to change, got %v", got)
+	}
+}
+
+func TestDiffEvents_TagsCleared(t *testing.T) {
+	old := concertcloud.Event{City: "Bern", Location: "Dampfzentrale", Date: time.Date(2031, 5, 1, 19, 0, 0, 0, time.UTC)}
+	old.Address.Geolocacation.Coordinates = []float64{7.4436, 46.9426}
+
+	e := old
+	e.City = "Bümpliz"
+	if patch, _ := diffEvents(defaultJob("bern"), uuid.New(), old, e); clearsTags(patch) {
+		t.Errorf("expected changed tags to be patched, got %v", patch.Tags)
+	}
+
+	e.City, e.Location = "", " "
+	patch, changes := diffEvents(defaultJob("bern"), uuid.New(), old, e)
+	if !clearsTags(patch) {
+		t.Errorf("expected the tags to be cleared, got %v", patch.Tags)
+	}
+	if !slices.ContainsFunc(changes, func(c Change) bool { return c.Field == "tags" && c.New == "" }) {
+		t.Errorf("expected the cleared tags in the changelog, got %v", changes)
+	}
+}
+
+func TestDiffEvents_KeepsPicture(t *testing.T) {
+	// without an ImageURL in the patch the picture, and so its media UUID,
+	// is left as it is on Mobilizòn
+	old := concertcloud.Event{Title: "Erika Stucky", ImageURL: "https://dampfzentrale.ch/erika.jpg"}
+	old.Address.Geolocacation.Coordinates = []float64{7.4436, 46.9426}
+	e := old
+	e.Title = "Erika Stucky & Band"
+	patch, _ := diffEvents(defaultJob("bern"), uuid.New(), old, e)
+	if patch.ImageURL != nil {
+		t.Errorf("expected no image in the patch, got %q", *patch.ImageURL)
+	}
+}
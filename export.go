@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+	"github.com/markjaroski/go-mobilizon-bot/feed"
+	"github.com/markjaroski/go-mobilizon-bot/mobilizon"
+	"github.com/markjaroski/go-mobilizon-bot/source"
+)
+
+// what the exported feeds are split by
+const EXPORT_BY_JOB = "job"
+const EXPORT_BY_CITY = "city"
+const EXPORT_BY_VENUE = "venue"
+
+// the feed formats, named by their file extension
+var exportFormats = map[string]func(io.Writer, feed.Feed) error{
+	"ics":  feed.WriteICS,
+	"rss":  feed.WriteRSS,
+	"atom": feed.WriteAtom,
+}
+
+// exportFeeds writes the future events of the cache as one feed per job,
+// city or venue into dir, in each of the formats, and returns the files
+// written. Names which give the same file name, such as the same venue
+// spelled in another case, share a feed.
+func exportFeeds(events map[string]ExistingEvent, by string, formats []string, dir string, now time.Time) ([]string, error) {
+	groups := make(map[string]*exportGroup)
+	for _, ev := range events {
+		if ev.Event.Date.Before(now) {
+			continue
+		}
+		var name string
+		switch by {
+		case EXPORT_BY_JOB:
+			name = ev.Job
+		case EXPORT_BY_CITY:
+			name = ev.Event.City
+		case EXPORT_BY_VENUE:
+			name = ev.Event.City + " " + ev.Event.Location
+		default:
+			return nil, fmt.Errorf("unknown export grouping %q, expected job, city or venue", by)
+		}
+		name = strings.TrimSpace(name)
+		key := slug(name)
+		if key == "" {
+			key, name = "other", "other"
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &exportGroup{name: name}
+			groups[key] = g
+		}
+		// the same spelling is picked whichever order the cache is read in
+		if name < g.name {
+			g.name = name
+		}
+		g.events = append(g.events, ev)
+	}
+	for _, f := range formats {
+		if _, ok := exportFormats[f]; !ok {
+			return nil, fmt.Errorf("unknown export format %q, expected ics, rss or atom", f)
+		}
+	}
+
+	var files []string
+	for key, g := range groups {
+		slices.SortFunc(g.events, func(a, b ExistingEvent) int { return a.Event.Date.Compare(b.Event.Date) })
+		f := exportFeed(g.name, g.events, now)
+		for _, format := range formats {
+			var buf bytes.Buffer
+			if err := exportFormats[format](&buf, f); err != nil {
+				return files, err
+			}
+			path := filepath.Join(dir, key+"."+format)
+			if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+				return files, err
+			}
+			files = append(files, path)
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// exportGroup is the events of one exported feed
+type exportGroup struct {
+	name   string
+	events []ExistingEvent
+}
+
+// exportFeed builds the feed of one group, linking every event to where
+// it is published on Mobilizòn
+func exportFeed(name string, events []ExistingEvent, now time.Time) feed.Feed {
+	base := strings.TrimSuffix(runConfig.MobilizonUrl, "/")
+	f := feed.Feed{
+		Title:   name + " – " + runConfig.AppName,
+		Link:    base,
+		ID:      base + "/export/" + slug(name),
+		Updated: now,
+	}
+	for _, ev := range events {
+		e := ev.Event
+		start := e.Date.In(exportLocation(ev.Job))
+		location := e.Location
+		if street := strings.TrimSpace(e.Address.HouseNumber + " " + e.Address.Street); street != "" {
+			location += ", " + street
+		}
+		if place := strings.TrimSpace(e.Address.PostCode + " " + e.City); place != "" {
+			location += ", " + place
+		}
+		f.Items = append(f.Items, feed.Item{
+			UID:      ev.UUID.String(),
+			URL:      base + "/events/" + ev.UUID.String(),
+			Title:    e.Title,
+			Summary:  e.Comment,
+			Start:    start,
+			End:      start.Add(2 * time.Hour),
+			Location: strings.TrimPrefix(location, ", "),
+			ImageURL: e.ImageURL,
+			Tags:     []string{e.Location, e.City},
+		})
+	}
+	return f
+}
+
+// exportLocation is the timezone of the job an event came from
+func exportLocation(job string) *time.Location {
+	tz := *opts.Timezone
+	for _, j := range runConfig.Jobs {
+		if j.Name == job && j.Timezone != "" {
+			tz = j.Timezone
+		}
+	}
+	// the timezone was checked with the run configuration
+	l, _ := time.LoadLocation(tz)
+	return l
+}
+
+// slug turns a name into a file name
+func slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// exportBaseURL makes links point at the instance the bot is registered
+// with, which may differ from the configured default
+func exportBaseURL() {
+	if reg, err := mobilizon.LoadRegistration(*opts.Config + "/registration.json"); err == nil && reg.BaseURL != "" {
+		runConfig.MobilizonUrl = reg.BaseURL
+	}
+}
+
+// groupEvents converts the events of a group people created by hand back
+// into ConcertCloud events. Drafts, cancelled events and the events the
+// bot mirrored itself, found by their UUID in the cache, are left out.
+func groupEvents(events []mobilizon.Event, cache map[string]ExistingEvent, sourceURL string) []concertcloud.Event {
+	mirrored := make(map[uuid.UUID]bool)
+	for _, ev := range cache {
+		mirrored[ev.UUID] = true
+	}
+	result := []concertcloud.Event{}
+	for _, e := range events {
+		if e.Draft || e.Status == mobilizon.EventStatusCancelled || mirrored[e.UUID] {
+			continue
+		}
+		result = append(result, source.FromMobilizon(e, sourceURL))
+	}
+	return result
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+	"github.com/markjaroski/go-mobilizon-bot/mobilizon"
+)
+
+// useRunConfig replaces the run configuration for the test
+func useRunConfig(t *testing.T, rc *RunConfig) {
+	t.Helper()
+	saved := runConfig
+	runConfig = rc
+	t.Cleanup(func() { runConfig = saved })
+}
+
+func TestExportFeeds(t *testing.T) {
+	useTimezone(t, "Europe/Zurich")
+	useRunConfig(t, &RunConfig{MobilizonUrl: "https://mobilisons.ch/", AppName: "Concert Cloud"})
+	now := time.Date(2031, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	cached := func(job string, city string, location string, days int) ExistingEvent {
+		return ExistingEvent{
+			UUID: uuid.New(),
+			Job:  job,
+			Event: concertcloud.Event{
+				Title:    location + " " + city,
+				City:     city,
+				Location: location,
+				Date:     now.Add(time.Duration(days) * 24 * time.Hour),
+			},
+		}
+	}
+	events := map[string]ExistingEvent{}
+	for i, ev := range []ExistingEvent{
+		cached("bern", "Bern", "Dampfzentrale", 1),
+		// the same venue, spelled differently by another source
+		cached("bern", "Bern", "dampfzentrale", 2),
+		cached("bern", "Bern", "Reitschule", 3),
+		cached("zurich", "Zürich", "Rote Fabrik", 4),
+		// past events are left out
+		cached("zurich", "Zürich", "Moods", -1),
+		// names without a letter or digit
+		cached("", "", "", 5),
+		cached("!!!", "?", "–", 6),
+	} {
+		events[string(rune('a'+i))] = ev
+	}
+
+	tests := []struct {
+		by    string
+		files []string
+		items map[string]int
+	}{
+		{EXPORT_BY_JOB, []string{"bern", "other", "zurich"}, map[string]int{"bern": 3, "other": 2, "zurich": 1}},
+		{EXPORT_BY_CITY, []string{"bern", "other", "zürich"}, map[string]int{"bern": 3, "other": 2, "zürich": 1}},
+		{
+			EXPORT_BY_VENUE,
+			[]string{"bern-dampfzentrale", "bern-reitschule", "other", "zürich-rote-fabrik"},
+			map[string]int{"bern-dampfzentrale": 2, "bern-reitschule": 1, "other": 2, "zürich-rote-fabrik": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			dir := t.TempDir()
+			files, err := exportFeeds(events, tt.by, []string{"ics", "rss"}, dir, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want []string
+			for _, name := range tt.files {
+				want = append(want, filepath.Join(dir, name+".ics"), filepath.Join(dir, name+".rss"))
+			}
+			if strings.Join(files, "\n") != strings.Join(want, "\n") {
+				t.Errorf("expected files\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(files, "\n"))
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != len(want) {
+				t.Errorf("expected %d files in the directory, got %d", len(want), len(entries))
+			}
+
+			for name, n := range tt.items {
+				dat, err := os.ReadFile(filepath.Join(dir, name+".ics"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := strings.Count(string(dat), "BEGIN:VEVENT"); got != n {
+					t.Errorf("%s: expected %d events, got %d", name, n, got)
+				}
+				if strings.Contains(string(dat), "Moods") {
+					t.Errorf("%s: expected the past event to be left out", name)
+				}
+			}
+		})
+	}
+}
+
+func TestExportFeeds_SameSpelling(t *testing.T) {
+	useTimezone(t, "Europe/Zurich")
+	useRunConfig(t, &RunConfig{MobilizonUrl: "https://mobilisons.ch", AppName: "Concert Cloud"})
+	now := time.Date(2031, 5, 1, 12, 0, 0, 0, time.UTC)
+	events := map[string]ExistingEvent{
+		"a": {UUID: uuid.New(), Event: concertcloud.Event{Title: "A", City: "bern", Date: now.Add(time.Hour)}},
+		"b": {UUID: uuid.New(), Event: concertcloud.Event{Title: "B", City: "Bern", Date: now.Add(2 * time.Hour)}},
+	}
+	// whichever spelling comes first in the cache, the feed is titled alike
+	for range 10 {
+		dir := t.TempDir()
+		if _, err := exportFeeds(events, EXPORT_BY_CITY, []string{"ics"}, dir, now); err != nil {
+			t.Fatal(err)
+		}
+		dat, err := os.ReadFile(filepath.Join(dir, "bern.ics"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(dat), "X-WR-CALNAME:Bern – Concert Cloud") {
+			t.Fatalf("expected the feed to be titled Bern, got\n%s", dat)
+		}
+	}
+}
+
+func TestExportFeeds_Invalid(t *testing.T) {
+	useTimezone(t, "Europe/Zurich")
+	useRunConfig(t, &RunConfig{})
+	events := map[string]ExistingEvent{"a": {Event: concertcloud.Event{Date: time.Now().Add(time.Hour)}}}
+	if _, err := exportFeeds(events, "country", []string{"ics"}, t.TempDir(), time.Now()); err == nil {
+		t.Error("expected an error for an unknown grouping")
+	}
+	if _, err := exportFeeds(events, EXPORT_BY_JOB, []string{"pdf"}, t.TempDir(), time.Now()); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestSlug(t *testing.T) {
+	for name, want := range map[string]string{
+		"Bern Dampfzentrale":        "bern-dampfzentrale",
+		"Bern dampfzentrale":        "bern-dampfzentrale",
+		"  Zürich / Moods  ":        "zürich-moods",
+		"Kaserne Basel (Rossstall)": "kaserne-basel-rossstall",
+		"?!":                        "",
+	} {
+		if got := slug(name); got != want {
+			t.Errorf("slug(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGroupEvents(t *testing.T) {
+	mirrored := uuid.New()
+	cache := map[string]ExistingEvent{"Bern/Dampfzentrale/2031-05-01T19:00:00Z": {UUID: mirrored}}
+	event := func(title string, edit func(e *mobilizon.Event)) mobilizon.Event {
+		e := mobilizon.Event{
+			UUID:     uuid.New(),
+			Title:    title,
+			URL:      "https://mobilisons.ch/events/" + title,
+			BeginsOn: time.Date(2031, 5, 1, 19, 0, 0, 0, time.UTC),
+			Status:   mobilizon.EventStatusConfirmed,
+		}
+		if edit != nil {
+			edit(&e)
+		}
+		return e
+	}
+
+	tests := []struct {
+		name  string
+		event mobilizon.Event
+		kept  bool
+	}{
+		{"made by hand", event("hand", nil), true},
+		{"tentative", event("tentative", func(e *mobilizon.Event) { e.Status = mobilizon.EventStatusTentative }), true},
+		{"draft", event("draft", func(e *mobilizon.Event) { e.Draft = true }), false},
+		{"cancelled", event("cancelled", func(e *mobilizon.Event) { e.Status = mobilizon.EventStatusCancelled }), false},
+		{"mirrored by the bot", event("mirrored", func(e *mobilizon.Event) { e.UUID = mirrored }), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := groupEvents([]mobilizon.Event{tt.event}, cache, "https://mobilisons.ch/@jazz")
+			if kept := len(got) == 1; kept != tt.kept {
+				t.Fatalf("expected kept=%v, got %+v", tt.kept, got)
+			}
+			if tt.kept && (got[0].URL != tt.event.URL || got[0].SourceURL != "https://mobilisons.ch/@jazz") {
+				t.Errorf("expected the event to keep its URL and name the group, got %+v", got[0])
+			}
+		})
+	}
+
+	// nothing left gives an empty list rather than null
+	if got := groupEvents(nil, cache, ""); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty list, got %#v", got)
+	}
+}
@@ -0,0 +1,52 @@
+// Package feed renders lists of events as iCalendar, RSS and Atom feeds,
+// for sites which embed the calendar we publish
+package feed
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Item is one event of a feed
+type Item struct {
+	// UID is the UUID of the event
+	UID string
+	// URL is where the event is published
+	URL      string
+	Title    string
+	Summary  string
+	Start    time.Time
+	End      time.Time
+	Location string
+	ImageURL string
+	Tags     []string
+}
+
+// Feed is a titled list of events
+type Feed struct {
+	Title string
+	// Link is the page the feed belongs to, and ID a unique URI for it
+	Link    string
+	ID      string
+	Updated time.Time
+	Items   []Item
+}
+
+var tags = regexp.MustCompile(`(?s)<[^>]*>`)
+var scripts = regexp.MustCompile(`(?is)<(script|style)\b.*?</(script|style)\s*>`)
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// plainText turns an HTML description into text for formats which can't
+// carry markup
+func plainText(s string) string {
+	s = scripts.ReplaceAllString(s, "")
+	s = strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n", "</p>", "\n\n").Replace(s)
+	s = html.UnescapeString(tags.ReplaceAllString(s, ""))
+	lines := strings.Split(s, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
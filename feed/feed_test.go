@@ -0,0 +1,139 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() Feed {
+	start := time.Date(2026, 11, 1, 20, 0, 0, 0, time.UTC)
+	return Feed{
+		Title:   "Lausanne",
+		Link:    "https://mobilizon.example",
+		ID:      "https://mobilizon.example/export/lausanne",
+		Updated: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+		Items: []Item{{
+			UID:      "8e7c4fc4-5d0b-4b4c-9d1c-2c1f5f4b6a01",
+			URL:      "https://mobilizon.example/events/8e7c4fc4-5d0b-4b4c-9d1c-2c1f5f4b6a01",
+			Title:    "Rock, Pop; and more",
+			Summary:  "<p>Doors at 19:00 &amp; show at 20:00.</p><p>Tickets at the door, with a description long enough to be folded.</p>",
+			Start:    start,
+			End:      start.Add(2 * time.Hour),
+			Location: "Salle des fêtes, Rue du Lac 1, 1000 Lausanne",
+			ImageURL: "https://mobilizon.example/media/poster.png",
+			Tags:     []string{"Salle des fêtes", "Lausanne"},
+		}},
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, testFeed()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:8e7c4fc4-5d0b-4b4c-9d1c-2c1f5f4b6a01\r\n",
+		"DTSTART:20261101T200000Z\r\n",
+		"DTEND:20261101T220000Z\r\n",
+		`SUMMARY:Rock\, Pop\; and more` + "\r\n",
+		`DESCRIPTION:Doors at 19:00 & show at 20:00.\n\nTickets at the door` + `\, with a description long enough to be folded.` + "\r\n",
+		`LOCATION:Salle des fêtes\, Rue du Lac 1\, 1000 Lausanne` + "\r\n",
+		"URL:https://mobilizon.example/events/8e7c4fc4-5d0b-4b4c-9d1c-2c1f5f4b6a01\r\n",
+		`CATEGORIES:Salle des fêtes,Lausanne` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("expected %q in\n%s", want, unfolded)
+		}
+	}
+}
+
+func TestWriteICS_FoldsMultibyte(t *testing.T) {
+	f := testFeed()
+	f.Items[0].Title = strings.Repeat("é", 60)
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+	unfolded := strings.ReplaceAll(buf.String(), "\r\n ", "")
+	if !strings.Contains(unfolded, "SUMMARY:"+strings.Repeat("é", 60)+"\r\n") {
+		t.Errorf("folding broke a character:\n%s", buf.String())
+	}
+}
+
+func TestWriteRSS(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteRSS(&buf, testFeed()); err != nil {
+		t.Fatal(err)
+	}
+	var doc rss
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid RSS: %v\n%s", err, buf.String())
+	}
+	if len(doc.Channel.Items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(doc.Channel.Items))
+	}
+	it := doc.Channel.Items[0]
+	if it.Link != testFeed().Items[0].URL || it.GUID.Value != testFeed().Items[0].UID || it.GUID.IsPermaLink {
+		t.Errorf("unexpected item %+v", it)
+	}
+	if it.PubDate != "Sun, 01 Nov 2026 20:00:00 +0000" {
+		t.Errorf("unexpected date %s", it.PubDate)
+	}
+	if !strings.HasPrefix(it.Description, "<p>Sun 1 Nov 2026 20:00 UTC, Salle des fêtes") {
+		t.Errorf("expected when and where first, got %q", it.Description)
+	}
+	if it.Enclosure == nil || it.Enclosure.Type != "image/png" {
+		t.Errorf("unexpected enclosure %+v", it.Enclosure)
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteAtom(&buf, testFeed()); err != nil {
+		t.Fatal(err)
+	}
+	var doc atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid Atom: %v\n%s", err, buf.String())
+	}
+	if doc.ID != testFeed().ID || len(doc.Entries) != 1 {
+		t.Fatalf("unexpected feed %+v", doc)
+	}
+	e := doc.Entries[0]
+	if e.ID != "urn:uuid:8e7c4fc4-5d0b-4b4c-9d1c-2c1f5f4b6a01" || e.Published != "2026-11-01T20:00:00Z" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if len(e.Links) != 2 || e.Links[0].Rel != "alternate" || e.Links[0].Href != testFeed().Items[0].URL {
+		t.Errorf("unexpected links %+v", e.Links)
+	}
+	if e.Content.Type != "html" || !strings.Contains(e.Content.Value, "<p>Doors at 19:00 &amp; show at 20:00.</p>") {
+		t.Errorf("unexpected content %+v", e.Content)
+	}
+}
+
+func TestDescribe_KeepsOnlyText(t *testing.T) {
+	it := testFeed().Items[0]
+	it.Summary = `<p onclick="steal()">Free entry<script>alert(document.cookie)</script></p><img src=x onerror="steal()">Bring <b>friends</b><br>and family`
+	got := describe(it)
+	for _, bad := range []string{"<script", "<img", "onclick", "onerror", "<b>"} {
+		if strings.Contains(got, bad) {
+			t.Errorf("expected %s to be removed, got %q", bad, got)
+		}
+	}
+	if !strings.Contains(got, "<p>Free entry</p>\n<p>Bring friends<br>and family</p>") {
+		t.Errorf("expected the text in paragraphs, got %q", got)
+	}
+}
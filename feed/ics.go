@@ -0,0 +1,82 @@
+package feed
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// the iCalendar form of a UTC time
+const icsTime = "20060102T150405Z"
+
+// WriteICS writes the feed as an iCalendar file (RFC 5545)
+func WriteICS(w io.Writer, f Feed) error {
+	b := bufio.NewWriter(w)
+	line := func(name string, value string) {
+		writeFolded(b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//go-mobilizon-bot//export//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeText(f.Title))
+	for _, it := range f.Items {
+		line("BEGIN", "VEVENT")
+		line("UID", escapeText(it.UID))
+		line("DTSTAMP", f.Updated.UTC().Format(icsTime))
+		line("DTSTART", it.Start.UTC().Format(icsTime))
+		if !it.End.IsZero() {
+			line("DTEND", it.End.UTC().Format(icsTime))
+		}
+		line("SUMMARY", escapeText(it.Title))
+		if it.Summary != "" {
+			line("DESCRIPTION", escapeText(plainText(it.Summary)))
+		}
+		if it.Location != "" {
+			line("LOCATION", escapeText(it.Location))
+		}
+		if it.URL != "" {
+			line("URL", it.URL)
+		}
+		if it.ImageURL != "" {
+			line("IMAGE;VALUE=URI", it.ImageURL)
+		}
+		if len(it.Tags) > 0 {
+			escaped := make([]string, len(it.Tags))
+			for i, t := range it.Tags {
+				escaped[i] = escapeText(t)
+			}
+			line("CATEGORIES", strings.Join(escaped, ","))
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return b.Flush()
+}
+
+// escapeText escapes a TEXT value
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeFolded writes a content line, folded so that no line is longer
+// than 75 octets, without splitting a UTF-8 character
+func writeFolded(b *bufio.Writer, s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// the leading space counts towards the next line
+		limit = 74
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
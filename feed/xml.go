@@ -0,0 +1,197 @@
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+)
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link,omitempty"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Description string        `xml:"description,omitempty"`
+	Categories  []string      `xml:"category"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length int    `xml:"length,attr"`
+}
+
+// WriteRSS writes the feed as RSS 2.0. RSS has no field for when an event
+// takes place, so the start is given as the publication date and at the
+// head of the description.
+func WriteRSS(w io.Writer, f Feed) error {
+	doc := rss{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Title,
+			LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+		},
+	}
+	for _, it := range f.Items {
+		item := rssItem{
+			Title:       it.Title,
+			Link:        it.URL,
+			GUID:        rssGUID{Value: it.UID},
+			PubDate:     it.Start.UTC().Format(time.RFC1123Z),
+			Description: describe(it),
+			Categories:  it.Tags,
+		}
+		if it.ImageURL != "" {
+			item.Enclosure = &rssEnclosure{URL: it.ImageURL, Type: imageType(it.ImageURL)}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+	return writeXML(w, doc)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Links      []atomLink     `xml:"link"`
+	Content    atomContent    `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// WriteAtom writes the feed as Atom (RFC 4287), with the start of each
+// event as its publication date
+func WriteAtom(w io.Writer, f Feed) error {
+	updated := f.Updated.UTC().Format(time.RFC3339)
+	doc := atomFeed{
+		Title:   f.Title,
+		ID:      f.ID,
+		Updated: updated,
+		Links:   []atomLink{{Href: f.Link}},
+	}
+	for _, it := range f.Items {
+		entry := atomEntry{
+			Title:     it.Title,
+			ID:        "urn:uuid:" + it.UID,
+			Updated:   updated,
+			Published: it.Start.UTC().Format(time.RFC3339),
+			Content:   atomContent{Type: "html", Value: describe(it)},
+		}
+		if it.URL != "" {
+			entry.Links = append(entry.Links, atomLink{Href: it.URL, Rel: "alternate"})
+		}
+		if it.ImageURL != "" {
+			entry.Links = append(entry.Links, atomLink{Href: it.ImageURL, Rel: "enclosure", Type: imageType(it.ImageURL)})
+		}
+		for _, t := range it.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: t})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return writeXML(w, doc)
+}
+
+// describe heads the description with when and where the event is, as
+// feed readers show little else. The summary is HTML from whoever published
+// the event, and partner sites embed the feeds, so only its text is kept,
+// in paragraphs.
+func describe(it Item) string {
+	desc := "<p>" + it.Start.Format("Mon 2 Jan 2006 15:04 MST")
+	if it.Location != "" {
+		desc += ", " + xmlEscape(it.Location)
+	}
+	desc += "</p>"
+	text := plainText(it.Summary)
+	if text == "" {
+		return desc
+	}
+	for _, para := range strings.Split(text, "\n\n") {
+		lines := strings.Split(para, "\n")
+		for i := range lines {
+			lines[i] = xmlEscape(lines[i])
+		}
+		desc += "\n<p>" + strings.Join(lines, "<br>") + "</p>"
+	}
+	return desc
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func imageType(u string) string {
+	u = strings.ToLower(strings.Split(u, "?")[0])
+	switch {
+	case strings.HasSuffix(u, ".png"):
+		return "image/png"
+	case strings.HasSuffix(u, ".webp"):
+		return "image/webp"
+	case strings.HasSuffix(u, ".gif"):
+		return "image/gif"
+	case strings.HasSuffix(u, ".avif"):
+		return "image/avif"
+	}
+	return "image/jpeg"
+}
+
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
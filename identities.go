@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/markjaroski/go-mobilizon-bot/mobilizon"
+)
+
+// resolveIdentityFlags turns --actor and --group into IDs. Both take an ID,
+// or a username which is looked up among the profiles of the authorized
+// user and the groups they are members of.
+func resolveIdentityFlags(ctx context.Context) error {
+	actorID, err := resolveIdentity(ctx, *opts.Actor, (*mobilizon.Client).ResolveActor)
+	if err != nil {
+		return fmt.Errorf("--actor: %w", err)
+	}
+	groupID, err := resolveIdentity(ctx, *opts.Group, (*mobilizon.Client).ResolveGroup)
+	if err != nil {
+		return fmt.Errorf("--group: %w", err)
+	}
+	opts.ActorID, opts.GroupID = &actorID, &groupID
+	return nil
+}
+
+// resolveJobIdentities turns the usernames given as actor and group in
+// bot.yml into IDs, the same way as those given as flags
+func resolveJobIdentities(ctx context.Context, jobs []JobConfig) error {
+	for i := range jobs {
+		j := &jobs[i]
+		var err error
+		if j.Actor != "" {
+			if j.ActorID, err = resolveIdentity(ctx, j.Actor, (*mobilizon.Client).ResolveActor); err != nil {
+				return fmt.Errorf("job %s: actor: %w", j.Name, err)
+			}
+		}
+		if j.Group != "" {
+			if j.GroupID, err = resolveIdentity(ctx, j.Group, (*mobilizon.Client).ResolveGroup); err != nil {
+				return fmt.Errorf("job %s: group: %w", j.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveIdentity returns the ID an --actor or --group value stands for,
+// connecting only when it is a username
+func resolveIdentity(ctx context.Context, value string, resolve func(*mobilizon.Client, context.Context, string) (string, error)) (int, error) {
+	if value == "" {
+		return -1, nil
+	}
+	if id, err := strconv.Atoi(value); err == nil {
+		return id, nil
+	}
+	connect(ctx)
+	id, err := resolve(mobClient, ctx, value)
+	if err != nil {
+		return -1, err
+	}
+	Log.Info("Resolved username", "username", value, "id", id)
+	return strconv.Atoi(id)
+}
+
+// preflight checks, before anything is published, that every job's actor
+// is one of the authorized user's profiles and may create events for its
+// group, and that the user granted the scopes the jobs need. Otherwise
+// each event would fail on its own deep into the run.
+func preflight(ctx context.Context, jobs []JobConfig) error {
+	identities, err := mobClient.Identities(ctx)
+	if err != nil {
+		return fmt.Errorf("pre-flight check: %w", err)
+	}
+
+	var problems []error
+	checked := make(map[[2]int]bool)
+	for _, job := range jobs {
+		pair := [2]int{job.ActorID, job.GroupID}
+		if checked[pair] {
+			continue
+		}
+		checked[pair] = true
+
+		if job.ActorID <= 0 {
+			problems = append(problems, fmt.Errorf("job %s: no actor given, use --actor or actor in %s", job.Name, RUN_CONFIG_FILE))
+			continue
+		}
+		group := ""
+		if job.GroupID > 0 {
+			group = strconv.Itoa(job.GroupID)
+		}
+		if err := mobilizon.CheckAttribution(identities, strconv.Itoa(job.ActorID), group); err != nil {
+			problems = append(problems, fmt.Errorf("job %s: %w", job.Name, err))
+		}
+	}
+
+	missing, known := mobClient.MissingScopes(requiredScopes(jobs))
+	switch {
+	case !known:
+		Log.Warn("The scopes granted to the bot are unknown, authorize it again to have them checked", "authconfig", *opts.AuthConfig)
+	case len(missing) > 0:
+		problems = append(problems, fmt.Errorf("the bot was not granted %s, remove %s and authorize it again", strings.Join(missing, ", "), *opts.AuthConfig))
+	}
+	return errors.Join(problems...)
+}
+
+// requiredScopes lists the scopes the jobs need: those for publishing, and
+// the one for deleting events when a job deletes the events which vanish
+// from its source
+func requiredScopes(jobs []JobConfig) []string {
+	scopes := mobilizon.PublishScopes()
+	if slices.ContainsFunc(jobs, func(j JobConfig) bool { return j.Vanished == MIRRORED_DELETE }) {
+		scopes = append(scopes, "write:event:delete")
+	}
+	return scopes
+}
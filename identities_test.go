@@ -0,0 +1,54 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRequiredScopes(t *testing.T) {
+	keep := JobConfig{Name: "keep", Vanished: MIRRORED_KEEP}
+	cancel := JobConfig{Name: "cancel", Vanished: MIRRORED_CANCEL}
+	del := JobConfig{Name: "delete", Vanished: MIRRORED_DELETE}
+
+	if scopes := requiredScopes([]JobConfig{keep, cancel}); slices.Contains(scopes, "write:event:delete") {
+		t.Errorf("expected no delete scope without a deleting job, got %v", scopes)
+	}
+	if scopes := requiredScopes([]JobConfig{keep, del}); !slices.Contains(scopes, "write:event:delete") {
+		t.Errorf("expected the delete scope for a deleting job, got %v", scopes)
+	}
+}
+
+func TestResolveJobIdentities(t *testing.T) {
+	fakeMobilizon(t, map[string]func(map[string]any) string{
+		"Identities": func(map[string]any) string {
+			return `{"data":{"identities":[
+				{"id":"12","preferredUsername":"venue","name":"Venue","domain":null,"memberships":{"total":1,"elements":[
+					{"id":"5","role":"MODERATOR","parent":{"id":"73","preferredUsername":"concerts","name":"Concerts","domain":null}}
+				]}}
+			]}}`
+		},
+	})
+
+	jobs := []JobConfig{
+		{Name: "byname", Actor: "@venue", Group: "@concerts", ActorID: -1, GroupID: -1},
+		{Name: "byid", Actor: "65691", Group: "73091", ActorID: 65691, GroupID: 73091},
+		{Name: "none", ActorID: -1, GroupID: -1},
+	}
+	if err := resolveJobIdentities(t.Context(), jobs); err != nil {
+		t.Fatal(err)
+	}
+	if jobs[0].ActorID != 12 || jobs[0].GroupID != 73 {
+		t.Errorf("expected actor 12 and group 73, got %d and %d", jobs[0].ActorID, jobs[0].GroupID)
+	}
+	if jobs[1].ActorID != 65691 || jobs[1].GroupID != 73091 {
+		t.Errorf("expected the IDs to be kept, got %d and %d", jobs[1].ActorID, jobs[1].GroupID)
+	}
+	if jobs[2].ActorID != -1 || jobs[2].GroupID != -1 {
+		t.Errorf("expected no actor or group, got %d and %d", jobs[2].ActorID, jobs[2].GroupID)
+	}
+
+	unknown := []JobConfig{{Name: "unknown", Actor: "@nobody"}}
+	if err := resolveJobIdentities(t.Context(), unknown); err == nil {
+		t.Error("expected an unknown username to be refused")
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/davecgh/go-spew/spew"
+
+	"github.com/markjaroski/go-mobilizon-bot/source"
+)
+
+// JobSummary counts what happened to the events of a single job
+type JobSummary struct {
+	Job        string
+	Fetched    int
+	Created    int
+	Updated    int
+	Unchanged  int
+	Skipped    int
+	Duplicates int
+	Failed     int
+	Missing    int
+	Retired    int
+	// what each scraper yielded, for sources made of several
+	Feeds []source.FeedResult
+	Err   error
+}
+
+// runJobs fetches and uploads the events of every job in turn. The
+// Mobilizòn client and the event cache are shared between the jobs; the
+// cache is saved once at the end so that no job drops another's entries.
+func runJobs(ctx context.Context, jobs []JobConfig) []JobSummary {
+	loadExistingEvents()
+
+	summaries := make([]JobSummary, 0, len(jobs))
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			Log.Info("Context cancelled: ", ctx.Err())
+			break
+		}
+
+		// pushed events arrive through serve
+		if job.Source == SOURCE_WEBHOOK {
+			Log.Debug("Skipping webhook job", "job", job.Name)
+			continue
+		}
+
+		Log.Info("Running job", "job", job.Name, "source", job.Source)
+		events, feeds, err := fetchEvents(ctx, job)
+		if err != nil {
+			// one broken feed should not stop the others
+			Log.Error("Error fetching events", "job", job.Name, "error", err)
+			var verr *source.ValidationError
+			if errors.As(err, &verr) {
+				for _, r := range verr.Records {
+					Log.Error("Invalid record", "job", job.Name, "file", verr.Name, "record", r.Record, "error", r.Err)
+				}
+			}
+			summaries = append(summaries, JobSummary{Job: job.Name, Err: err})
+			continue
+		}
+
+		// fetchAddrs(ctx, events)
+		summary := createEvents(ctx, job, events)
+		summary.Feeds = feeds
+		// the events of a failed scraper are missing, but haven't vanished
+		if slices.ContainsFunc(feeds, func(f source.FeedResult) bool { return f.Err != nil }) {
+			Log.Info("Not reconciling an incomplete scrape", "job", job.Name)
+		} else {
+			summary.Missing, summary.Retired = reconcileEvents(ctx, job, events)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	keepUnseenEvents()
+
+	Log.Debug("Saving existing events list")
+	Log.Trace("Saving existing events list", "events", spew.Sdump(created))
+	saveExistingEvents()
+	saveMediaCache()
+
+	logSummaries(summaries)
+	return summaries
+}
+
+// logSummaries writes one line per job
+func logSummaries(summaries []JobSummary) {
+	for _, s := range summaries {
+		if s.Err != nil {
+			Log.Error("Job failed", "job", s.Job, "error", s.Err)
+			continue
+		}
+		Log.Info("Job summary",
+			"job", s.Job,
+			"fetched", s.Fetched,
+			"created", s.Created,
+			"updated", s.Updated,
+			"unchanged", s.Unchanged,
+			"skipped", s.Skipped,
+			"duplicates", s.Duplicates,
+			"failed", s.Failed,
+			"missing", s.Missing,
+			"retired", s.Retired,
+		)
+		for _, f := range s.Feeds {
+			if f.Err != nil {
+				Log.Error("Scraper failed", "job", s.Job, "scraper", f.Name, "error", f.Err)
+				continue
+			}
+			Log.Info("Scraper summary", "job", s.Job, "scraper", f.Name, "items", f.Items)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package mobilizon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Khan/genqlient/graphql"
+)
+
+// apiClient sends the genqlient operations to Mobilizòn. Unlike the
+// genqlient client it keeps everything Mobilizòn says about an error, and
+// returns the error types of this package.
+type apiClient struct {
+	endpoint   string
+	httpClient graphql.Doer
+}
+
+// newAPIClient creates a GraphQL client posting to the API endpoint of an
+// instance
+func newAPIClient(endpoint string, httpClient graphql.Doer) graphql.Client {
+	return &apiClient{endpoint: endpoint, httpClient: httpClient}
+}
+
+func (c *apiClient) MakeRequest(ctx context.Context, req *graphql.Request, resp *graphql.Response) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	reqCtx := ctx
+	if strings.HasPrefix(strings.TrimSpace(req.Query), "mutation") {
+		reqCtx = withMutation(ctx)
+	}
+	httpReq, err := http.NewRequestWithContext(reqCtx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		// giving up is not the server's fault
+		if ctx.Err() != nil {
+			return err
+		}
+		return &UnavailableError{&RequestError{Operation: req.OpName, Err: err}}
+	}
+	defer httpResp.Body.Close()
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return &UnavailableError{&RequestError{Operation: req.OpName, StatusCode: httpResp.StatusCode, Err: err}}
+	}
+
+	// the data is decoded straight into the operation's response
+	result := struct {
+		Data       any            `json:"data"`
+		Extensions map[string]any `json:"extensions"`
+		Errors     []GraphQLError `json:"errors"`
+	}{Data: resp.Data}
+	jsonErr := json.Unmarshal(data, &result)
+	resp.Extensions = result.Extensions
+
+	if httpResp.StatusCode != http.StatusOK || len(result.Errors) > 0 {
+		// a crashed server tends to answer with an HTML page
+		if jsonErr != nil && len(result.Errors) == 0 {
+			result.Errors = []GraphQLError{{Message: string(truncate(data, 200))}}
+		}
+		return newRequestError(req.OpName, httpResp, result.Errors)
+	}
+	if jsonErr != nil {
+		return fmt.Errorf("%s: invalid response: %w", req.OpName, jsonErr)
+	}
+	return nil
+}
+
+// truncate shortens a response body for an error message
+func truncate(b []byte, n int) []byte {
+	if len(b) > n {
+		return append(b[:n:n], "..."...)
+	}
+	return b
+}
@@ -9,10 +9,13 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"os"
 	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Khan/genqlient/graphql"
@@ -24,13 +27,27 @@ import (
 // required because our server seems to crash once in a while
 const SERVER_CRASH_WAIT_TIME = time.Duration(1 * int64(time.Minute))
 
-// Client wraps the genqlient GraphQL client
+// Client wraps the genqlient GraphQL client. It may be shared by several
+// goroutines.
 type Client struct {
 	baseURL      string
 	clientID     string
 	oauth2Config *oauth2.Config
-	token        *oauth2.Token
-	gqlClient    graphql.Client
+	// mu guards the token, which is rotated when it is refreshed, and the
+	// auth file it is saved to
+	mu        sync.Mutex
+	token     *oauth2.Token
+	scopes    []string
+	tokenPath string
+	gqlClient graphql.Client
+	// public sends requests without authentication, such as the refresh
+	// of the token
+	public graphql.Client
+	// transport carries every request to the instance, through retries
+	// and the circuit breaker
+	transport  http.RoundTripper
+	resilience Resilience
+	media      *MediaCache
 }
 
 // NewClient creates a new Mobilizon client
@@ -39,16 +56,18 @@ func NewClient(baseURL string, clientID string) (*Client, error) {
 		return nil, errors.New("clientID is required - call mobilizon.RegisterApp() first")
 	}
 
-	return &Client{
-		baseURL:   baseURL,
-		clientID:  clientID,
-		gqlClient: graphql.NewClient(baseURL+"/api", http.DefaultClient),
+	c := &Client{
+		baseURL:    baseURL,
+		clientID:   clientID,
+		resilience: DefaultResilience(),
 		oauth2Config: &oauth2.Config{
 			ClientID: clientID,
 			Scopes: []string{
 				"write:event:create",
 				"write:event:update",
+				"write:event:delete",
 				"write:media:upload",
+				"write:media:remove",
 			},
 			Endpoint: oauth2.Endpoint{
 				AuthURL:       baseURL + "/oauth/authorize",
@@ -56,31 +75,39 @@ func NewClient(baseURL string, clientID string) (*Client, error) {
 				DeviceAuthURL: baseURL + "/login/device/code",
 			},
 		},
-	}, nil
+	}
+	c.initTransport()
+	return c, nil
 }
 
-// initGraphQLClient initializes the GraphQL client with auth
-func (c *Client) initGraphQLClient(ctx context.Context) {
-	httpClient := c.oauth2Config.Client(ctx, c.token)
-	c.gqlClient = graphql.NewClient(c.baseURL+"/api", httpClient)
+// NewPublicClient creates a client for the public API of an instance, such
+// as the events anyone can search. It never authorizes.
+func NewPublicClient(baseURL string) *Client {
+	c := &Client{
+		baseURL:      baseURL,
+		resilience:   DefaultResilience(),
+		oauth2Config: &oauth2.Config{},
+	}
+	c.initTransport()
+	return c
 }
 
 // performs the OAuth2 handshake to obtain an account holder's authorization
 // and then initializes the client with the refresh token
 //
-// This also handles token renewal
+// The tokens are then refreshed as needed, and saved to tokenPath whenever
+// they are rotated
 func (c *Client) EnsureAuthorization(ctx context.Context, tokenPath string) error {
+	c.tokenPath = tokenPath
 	if err := c.LoadToken(tokenPath); err == nil {
-		if err := c.RefreshToken(ctx, tokenPath); err == nil {
+		if _, err := c.validToken(ctx); err == nil {
 			return nil
 		}
 	}
 	if err := c.Authorize(ctx); err != nil {
 		return err
 	}
-	c.SaveToken(tokenPath)
-	c.initGraphQLClient(ctx)
-	return nil
+	return c.SaveToken(tokenPath)
 }
 
 // performs the OAuth2 device code flow to authorize our graphql client
@@ -89,6 +116,9 @@ func (c *Client) Authorize(ctx context.Context) error {
 		return fmt.Errorf("The OAuth2Config has no client ID. Call Register() or LoadClientID()")
 	}
 
+	// the device flow goes through the same retries as everything else
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: c.transport})
+
 	// get the device code
 	deviceAuth, err := c.oauth2Config.DeviceAuth(ctx)
 	if err != nil {
@@ -110,35 +140,28 @@ func (c *Client) Authorize(ctx context.Context) error {
 		return fmt.Errorf("failed to get access token: %w", err)
 	}
 
+	c.mu.Lock()
 	c.token = token
-
-	// set up an HTTPClient with automated retries
-	retryClient := retryablehttp.NewClient()
-	retryClient.RetryWaitMin = SERVER_CRASH_WAIT_TIME
-	retryClient.RetryWaitMax = time.Duration(10 * int64(time.Minute))
-	retryClient.RetryMax = 120
-	retryClient.CheckRetry = RetryPolicy
-	retryClient.Backoff = ErrorBackoff
-
-	httpClient := c.oauth2Config.Client(ctx, c.token)
-	retryClient.HTTPClient = httpClient
-	c.gqlClient = graphql.NewClient(c.baseURL+"/api", retryClient.StandardClient())
+	if scope, ok := token.Extra("scope").(string); ok {
+		c.scopes = strings.Fields(scope)
+	}
+	c.mu.Unlock()
 
 	return nil
 }
 
+// savedToken is a token as it is kept in the auth file, with the scopes
+// the user granted, which the token itself doesn't carry
+type savedToken struct {
+	*oauth2.Token
+	Scope string `json:"scope,omitempty"`
+}
+
 // SaveToken saves the token to a file
 func (c *Client) SaveToken(filepath string) error {
-	if c.token == nil {
-		return fmt.Errorf("no token to save")
-	}
-
-	data, err := json.MarshalIndent(c.token, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(filepath, data, 0600)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.saveToken(filepath)
 }
 
 // LoadToken loads a token from a file
@@ -148,30 +171,41 @@ func (c *Client) LoadToken(filepath string) error {
 		return err
 	}
 
-	var token oauth2.Token
+	token := savedToken{Token: &oauth2.Token{}}
 	if err := json.Unmarshal(data, &token); err != nil {
 		return err
 	}
 
-	c.token = &token
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token.Token
+	c.scopes = strings.Fields(token.Scope)
 	return nil
 }
 
-func (c *Client) RefreshToken(ctx context.Context, tokenPath string) error {
-	refreshToken := c.token.RefreshToken
-	c.gqlClient = graphql.NewClient(c.baseURL+"/api", http.DefaultClient)
-	resp, err := RefreshAuthTokens(ctx, c.gqlClient, refreshToken)
-	if err != nil {
-		return err
-	}
-	c.token = &oauth2.Token{
-		AccessToken:  resp.RefreshToken.AccessToken,
-		RefreshToken: resp.RefreshToken.RefreshToken,
-		Expiry:       time.Now().Add(time.Hour * 8),
+// MissingScopes returns the scopes among those required which the user
+// did not grant. The granted scopes are only known for authorizations made
+// since they are recorded; known is false for older ones.
+func (c *Client) MissingScopes(required []string) (missing []string, known bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.scopes) == 0 {
+		return nil, false
+	}
+	for _, r := range required {
+		// a scope also grants the narrower ones below it, as write does
+		// write:event:create
+		if !slices.ContainsFunc(c.scopes, func(s string) bool { return s == r || strings.HasPrefix(r, s+":") }) {
+			missing = append(missing, r)
+		}
 	}
-	c.SaveToken(tokenPath)
-	c.initGraphQLClient(ctx)
-	return nil
+	return missing, true
+}
+
+// SetMediaCache makes the client reuse pictures it has uploaded before
+// instead of uploading them again
+func (c *Client) SetMediaCache(m *MediaCache) {
+	c.media = m
 }
 
 // GraphQLClient returns the underlying GraphQL client for direct use
@@ -180,10 +214,15 @@ func (c *Client) GraphQLClient() graphql.Client {
 	return c.gqlClient
 }
 
-// HTTPClient returns an authenticated HTTP client
+// HTTPClient returns an authenticated HTTP client, which refreshes the
+// token as needed
 // Useful for other HTTP operations beyond GraphQL
 func (c *Client) HTTPClient(ctx context.Context) *http.Client {
-	return c.oauth2Config.Client(ctx, c.token)
+	base := c.transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &http.Client{Transport: &authTransport{c: c, base: base}}
 }
 
 // UploadMediaFile uploads a file and returns the media UUID
@@ -194,41 +233,106 @@ func (c *Client) UploadMediaFile(ctx context.Context, filepath string) (*uuid.UU
 		return nil, err
 	}
 
+	_, mediaUUID, err := c.uploadMedia(ctx, fileContents)
+	if err != nil {
+		return nil, err
+	}
+	return &mediaUUID, nil
+}
+
+// uploadImage downloads the picture at URL and uploads it, unless the
+// media cache shows that the same picture has been uploaded before. A URL
+// uploaded before is only downloaded again when its server says the
+// picture changed.
+func (c *Client) uploadImage(ctx context.Context, URL string) (*uuid.UUID, error) {
+	var cached MediaSource
+	var uploaded MediaEntry
+	if c.media != nil {
+		cached, uploaded, _ = c.media.source(URL)
+	}
+	path, src, err := downloadFile(URL, cached)
+	if errors.Is(err, errNotModified) {
+		return &uploaded.UUID, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("downloading image %s: %w", URL, err)
+	}
+	fileContents, _, err := loadFileContents(path)
+	if err != nil {
+		return nil, err
+	}
+
+	src.Hash = contentHash(fileContents)
+	if c.media != nil {
+		if e, ok := c.media.lookup(URL, src); ok {
+			return &e.UUID, nil
+		}
+	}
+
+	id, mediaUUID, err := c.uploadMedia(ctx, fileContents)
+	if err != nil {
+		return nil, fmt.Errorf("uploading image %s: %w", path, err)
+	}
+	if c.media != nil {
+		c.media.store(URL, src, MediaEntry{ID: id, UUID: mediaUUID, Hash: src.Hash, Uploaded: time.Now()})
+	}
+	return &mediaUUID, nil
+}
+
+// useMedia records that the picture was set on the event
+func (c *Client) useMedia(picture *MediaInput, eventUUID *uuid.UUID) {
+	if c.media != nil && picture != nil && picture.MediaUuid != nil && eventUUID != nil {
+		c.media.use(*picture.MediaUuid, *eventUUID)
+	}
+}
+
+// uploadMedia sends the picture to Mobilizòn and returns its ID and UUID
+func (c *Client) uploadMedia(ctx context.Context, fileContents []byte) (string, uuid.UUID, error) {
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
 
 	// TODO make this a template string or something to avoid the long line
-	writer.WriteField("query", "mutation uploadMedia($file: Upload!, $name: String!) { uploadMedia(file: $file, name: $name) { uuid } }")
+	writer.WriteField("query", "mutation uploadMedia($file: Upload!, $name: String!) { uploadMedia(file: $file, name: $name) { id uuid } }")
 	writer.WriteField("variables", "{\"name\":\"promotional image\",\"file\":\"image1\"}")
 
 	part, err := writer.CreateFormFile("image1", "promotional image")
 	if err != nil {
-		return nil, err
+		return "", uuid.Nil, err
 	}
 	part.Write(fileContents)
 	writer.Close()
 
-	r, err := http.NewRequest("POST", c.baseURL+"/api", body)
+	r, err := http.NewRequestWithContext(withMutation(ctx), "POST", c.baseURL+"/api", body)
 	if err != nil {
-		return nil, err
+		return "", uuid.Nil, err
 	}
 	r.Header.Add("Content-Type", writer.FormDataContentType())
-	r.Header.Add("Authorization", "Bearer "+c.token.AccessToken)
 
 	resp, err := c.HTTPClient(ctx).Do(r)
 	if err != nil {
-		return nil, err
+		return "", uuid.Nil, &UnavailableError{&RequestError{Operation: "uploadMedia", Err: err}}
 	}
+	defer resp.Body.Close()
 
 	respData, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return "", uuid.Nil, &UnavailableError{&RequestError{Operation: "uploadMedia", StatusCode: resp.StatusCode, Err: err}}
 	}
 
 	var respJSON UploadMediaResponse
-	json.Unmarshal(respData, &respJSON)
-	return &respJSON.Data.UploadMedia.UUID, nil
-
+	if err := json.Unmarshal(respData, &respJSON); err != nil && resp.StatusCode == http.StatusOK {
+		return "", uuid.Nil, fmt.Errorf("uploadMedia: invalid response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || len(respJSON.Errors) > 0 {
+		if len(respJSON.Errors) == 0 {
+			respJSON.Errors = []GraphQLError{{Message: string(truncate(respData, 200))}}
+		}
+		return "", uuid.Nil, newRequestError("uploadMedia", resp, respJSON.Errors)
+	}
+	if respJSON.Data.UploadMedia.UUID == uuid.Nil {
+		return "", uuid.Nil, fmt.Errorf("uploadMedia: no media returned")
+	}
+	return respJSON.Data.UploadMedia.ID, respJSON.Data.UploadMedia.UUID, nil
 }
 
 // CreateEvent creates an event
@@ -239,18 +343,14 @@ func (c *Client) CreateEvent(
 
 	var picture *MediaInput = nil
 
-	if params.ImageURL != "" {
-		var mi MediaInput
-		if path, err := downloadFile(params.ImageURL); err != nil {
-			return nil, errors.New("Error Downloading image: " + params.ImageURL + "error: " + err.Error())
-		} else {
-			if uuid, err := c.UploadMediaFile(ctx, path); err == nil {
-				mi.MediaUuid = uuid
-				picture = &mi
-			} else {
-				return nil, errors.New("Error uploading image: " + path)
-			}
+	if params.MediaUUID != nil {
+		picture = &MediaInput{MediaUuid: params.MediaUUID}
+	} else if params.ImageURL != "" {
+		uuid, err := c.uploadImage(ctx, params.ImageURL)
+		if err != nil {
+			return nil, err
 		}
+		picture = &MediaInput{MediaUuid: uuid}
 	}
 
 	endDate := strconv.Itoa(params.AttributedToId)
@@ -282,6 +382,7 @@ func (c *Client) CreateEvent(
 		return nil, err
 	}
 
+	c.useMedia(picture, resp.CreateEvent.Uuid)
 	return resp.CreateEvent.Uuid, nil
 }
 
@@ -293,18 +394,16 @@ func (c *Client) UpdateEvent(
 
 	var picture *MediaInput = nil
 
-	if params.ImageURL != "" {
-		var mi MediaInput
-		if path, err := downloadFile(params.ImageURL); err == nil {
-			if uuid, err := c.UploadMediaFile(ctx, path); err == nil {
-				mi.MediaUuid = uuid
-				picture = &mi
-			}
+	if params.MediaUUID != nil {
+		picture = &MediaInput{MediaUuid: params.MediaUUID}
+	} else if params.ImageURL != "" {
+		if uuid, err := c.uploadImage(ctx, params.ImageURL); err == nil {
+			picture = &MediaInput{MediaUuid: uuid}
 		}
 	}
 
 	// get the existing event ID using the UUID
-	fre, err := FetchEvent(ctx, c.gqlClient, *params.UUID)
+	id, err := c.eventID(ctx, *params.UUID)
 	if err != nil {
 		return nil, err
 	}
@@ -316,7 +415,7 @@ func (c *Client) UpdateEvent(
 	resp, err := UpdateEvent(
 		ctx,
 		c.gqlClient,
-		*fre.Event.FullEvent.Id,
+		id,
 		&params.Title,
 		&params.Description,
 		&params.BeginsOn,
@@ -340,9 +439,154 @@ func (c *Client) UpdateEvent(
 		return nil, err
 	}
 
+	c.useMedia(picture, resp.UpdateEvent.Uuid)
 	return resp.UpdateEvent.Uuid, nil
 }
 
+// PatchEvent sends only the changed fields of an existing event. The
+// picture is only looked at when ImageURL is set, and an empty ImageURL
+// leaves it as it is.
+func (c *Client) PatchEvent(ctx context.Context, patch EventPatch) (*uuid.UUID, error) {
+	var picture *MediaInput = nil
+
+	if patch.ImageURL != nil && *patch.ImageURL != "" {
+		uuid, err := c.uploadImage(ctx, *patch.ImageURL)
+		if err != nil {
+			return nil, err
+		}
+		picture = &MediaInput{MediaUuid: uuid}
+	}
+
+	id, err := c.eventID(ctx, patch.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := PatchEvent(
+		ctx,
+		c.gqlClient,
+		id,
+		patch.Title,
+		patch.Description,
+		patch.BeginsOn,
+		patch.EndsOn,
+		patch.ExternalParticipationURL,
+		patch.Tags,
+		picture,
+		patch.OnlineAddress,
+		patch.Category,
+		patch.PhysicalAddress,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.useMedia(picture, resp.UpdateEvent.Uuid)
+	return resp.UpdateEvent.Uuid, nil
+}
+
+// CancelEvent marks an existing event as cancelled, leaving the rest of
+// the event untouched
+func (c *Client) CancelEvent(ctx context.Context, eventUUID uuid.UUID) error {
+	id, err := c.eventID(ctx, eventUUID)
+	if err != nil {
+		return err
+	}
+	resp, err := CancelEvent(ctx, c.gqlClient, id)
+	if err != nil {
+		return err
+	}
+	if resp.UpdateEvent == nil || resp.UpdateEvent.Status == nil || *resp.UpdateEvent.Status != EventStatusCancelled {
+		return fmt.Errorf("event %s was not cancelled", eventUUID)
+	}
+	return nil
+}
+
+// DeleteEvent deletes an existing event
+func (c *Client) DeleteEvent(ctx context.Context, eventUUID uuid.UUID) error {
+	id, err := c.eventID(ctx, eventUUID)
+	if err != nil {
+		return err
+	}
+	resp, err := DeleteEvent(ctx, c.gqlClient, id)
+	if err != nil {
+		return err
+	}
+	if resp.DeleteEvent == nil || resp.DeleteEvent.Id == nil || *resp.DeleteEvent.Id != id {
+		return fmt.Errorf("event %s was not deleted", eventUUID)
+	}
+	return nil
+}
+
+// RemoveMedia deletes an uploaded picture
+func (c *Client) RemoveMedia(ctx context.Context, id string) error {
+	resp, err := RemoveMedia(ctx, c.gqlClient, id)
+	if err != nil {
+		return err
+	}
+	if resp.RemoveMedia == nil || resp.RemoveMedia.Id == nil || *resp.RemoveMedia.Id != id {
+		return fmt.Errorf("media %s was not removed", id)
+	}
+	return nil
+}
+
+// OrphanedMedia lists the cached pictures which none of the events we set
+// them on uses any more, because the event is gone or has another picture
+func (c *Client) OrphanedMedia(ctx context.Context) ([]MediaEntry, error) {
+	if c.media == nil {
+		return nil, errors.New("no media cache")
+	}
+
+	// the live picture of every event, fetched once
+	pictures := make(map[uuid.UUID]uuid.UUID)
+	var orphans []MediaEntry
+	for _, m := range c.media.Entries() {
+		used := false
+		for _, ev := range m.Events {
+			pic, ok := pictures[ev]
+			if !ok {
+				live, err := c.FetchEvent(ctx, ev)
+				// only a deleted event frees its picture, any other
+				// error might mean we can't see it
+				if err != nil && !isNotFound(err) {
+					return nil, err
+				}
+				if err == nil && live.Picture != nil {
+					pic = live.Picture.UUID
+				}
+				pictures[ev] = pic
+			}
+			if pic == m.UUID {
+				used = true
+				break
+			}
+		}
+		if !used {
+			orphans = append(orphans, m)
+		}
+	}
+	return orphans, nil
+}
+
+// isNotFound reports whether Mobilizòn said that what we asked for does
+// not exist
+func isNotFound(err error) bool {
+	var nf *NotFoundError
+	return errors.As(err, &nf)
+}
+
+// eventID resolves an event UUID to the internal ID the mutations expect
+func (c *Client) eventID(ctx context.Context, eventUUID uuid.UUID) (string, error) {
+	fre, err := FetchEvent(ctx, c.gqlClient, eventUUID)
+	if err != nil {
+		return "", err
+	}
+	if fre.Event == nil || fre.Event.FullEvent.Id == nil {
+		return "", notFound("FetchEvent", "event %s not found", eventUUID)
+	}
+	return *fre.Event.FullEvent.Id, nil
+}
+
 // // CreateOrUpdateEvent creates an event if params.UUID is nil, otherwise updates it
 // func (c *Client) CreateOrUpdateEvent(ctx context.Context, params EventParams) (*uuid.UUID, error) {
 // 	if params.UUID != nil {
@@ -371,6 +615,70 @@ func (c *Client) SearchForEvents(ctx context.Context, term string, beginsOn time
 	return events, nil
 }
 
+// SearchParams narrows a search for events. Fields left empty are not sent,
+// so the instance's defaults apply.
+type SearchParams struct {
+	Term string
+	// Location is a geohash, see Geohash, and Radius in kilometres
+	Location string
+	Radius   float64
+	Category string
+	BeginsOn time.Time
+	EndsOn   time.Time
+	Page     int
+	Limit    int
+}
+
+// SearchEventPage returns one page of the events matching the search, and
+// how many match in total
+func (c *Client) SearchEventPage(ctx context.Context, p SearchParams) ([]Event, int, error) {
+	resp, err := SearchEventsNear(ctx, c.gqlClient,
+		optional(p.Term), optional(p.Location), optional(p.Radius), optional(p.Category),
+		optional(p.BeginsOn), optional(p.EndsOn), optional(p.Page), optional(p.Limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.SearchEvents == nil {
+		return nil, 0, nil
+	}
+
+	events := make([]Event, 0, len(resp.SearchEvents.Elements))
+	for _, elem := range resp.SearchEvents.Elements {
+		if elem != nil {
+			events = append(events, *eventFromFullEvent(&elem.FullEvent))
+		}
+	}
+	return events, resp.SearchEvents.Total, nil
+}
+
+// GroupEvents returns every event a group organizes which begins after
+// the given time, page by page
+func (c *Client) GroupEvents(ctx context.Context, groupID string, after time.Time) ([]Event, error) {
+	const limit = 50
+	var events []Event
+	for page := 1; ; page++ {
+		resp, err := GroupEvents(ctx, c.gqlClient, groupID, optional(after), &page, optional(limit))
+		if err != nil {
+			return nil, err
+		}
+		if resp.GroupById == nil {
+			return nil, notFound("GroupEvents", "group %s not found", groupID)
+		}
+		list := resp.GroupById.OrganizedEvents
+		if list == nil {
+			return events, nil
+		}
+		for _, elem := range list.Elements {
+			if elem != nil {
+				events = append(events, *eventFromFullEvent(&elem.FullEvent))
+			}
+		}
+		if len(list.Elements) < limit || len(events) >= deref(list.Total) {
+			return events, nil
+		}
+	}
+}
+
 func (c *Client) EventExists(ctx context.Context, title string, location string, city string, beginsOn time.Time) (bool, *uuid.UUID, error) {
 
 	term := title
@@ -436,29 +744,74 @@ func (c *Client) FetchAddr(ctx context.Context, query string) ([]AddressInput, e
 	return addrs, nil
 }
 
-func (c *Client) FetchEvent(uuid.UUID) (*Event, error) {
-	return nil, errors.New("FetchEvents() not implemented.")
+// FetchEvent fetches a single event by UUID with everything Mobilizòn
+// knows about it
+func (c *Client) FetchEvent(ctx context.Context, eventUUID uuid.UUID) (*Event, error) {
+	resp, err := FetchEvent(ctx, c.gqlClient, eventUUID)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Event == nil {
+		return nil, notFound("FetchEvent", "event %s not found", eventUUID)
+	}
+	return eventFromFullEvent(&resp.Event.FullEvent), nil
+}
+
+// mutationKey marks the context of a request which changes something on
+// Mobilizòn
+type mutationKey struct{}
+
+// withMutation marks the requests made with the context as mutations
+func withMutation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, mutationKey{}, true)
+}
+
+func isMutation(ctx context.Context) bool {
+	m, _ := ctx.Value(mutationKey{}).(bool)
+	return m
+}
+
+// neverSent tells whether a request failed before it could leave, because
+// no connection to the server was made
+func neverSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
 }
 
-// mobilizònRetryPolicy implements the RetryPolicy interface from
-// hashicorp.retryablehttp, which captures the main failure modes cause by
-// an ephemeral crash of the Mobilizòn server process
+// RetryPolicy implements the RetryPolicy interface from
+// hashicorp.retryablehttp. It retries the failures an ephemeral crash of the
+// Mobilizòn server causes: no answer at all, or an error from the proxy in
+// front of it while it restarts, as well as being asked to slow down. A 401
+// is not retried, the token is refreshed instead.
+//
+// A mutation which reached the server may have been carried out before it
+// failed, and sending it again could publish a duplicate event. It is only
+// retried when the connection could not be made, or when the proxy says the
+// server is down.
 func RetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
-	if resp.Status == "401" {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	if errors.Is(err, ErrServerDown) {
+		return false, err
+	}
+	if err != nil && isMutation(ctx) {
+		return neverSent(err), nil
+	}
+	if err != nil {
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+	if resp == nil {
 		return true, nil
 	}
-	if resp.Status < "400" {
-		return false, nil
+	if isMutation(ctx) {
+		return serverDown(resp.StatusCode), nil
 	}
-	if resp.Status >= "405" {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusRequestTimeout:
+		return true, nil
+	case resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode != http.StatusNotImplemented:
 		return true, nil
 	}
 	return false, nil
 }
-
-// mobilizònErrorBackoff implements the Backoff interface from
-// hashicorp.retryablehttp, waiting long enough for Mobilizòn to recover
-// from an activity-pub related crash
-func ErrorBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
-	return SERVER_CRASH_WAIT_TIME
-}
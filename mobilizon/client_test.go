@@ -3,10 +3,17 @@ package mobilizon
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -43,10 +50,52 @@ func clientWithMock(mock *MockGraphQLClient) *Client {
 		baseURL:      "https://example.com",
 		clientID:     "test-client-id",
 		gqlClient:    mock,
+		public:       mock,
 		oauth2Config: &oauth2.Config{},
 	}
 }
 
+// graphQLServer starts an httptest server answering GraphQL operations by
+// name. Each handler receives the request variables and returns the JSON
+// body to send back.
+func graphQLServer(t *testing.T, handlers map[string]func(vars map[string]any) string) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			OperationName string         `json:"operationName"`
+			Query         string         `json:"query"`
+			Variables     map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid GraphQL request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		handler, ok := handlers[req.OperationName]
+		if !ok {
+			t.Errorf("unexpected operation %q", req.OperationName)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(handler(req.Variables)))
+	}))
+	t.Cleanup(server.Close)
+
+	return &Client{
+		baseURL:      server.URL,
+		clientID:     "test-client-id",
+		gqlClient:    newAPIClient(server.URL+"/api", server.Client()),
+		public:       newAPIClient(server.URL+"/api", server.Client()),
+		oauth2Config: &oauth2.Config{},
+	}
+}
+
+// fetchEventFound answers FetchEvent with an event whose internal ID is 42
+func fetchEventFound(vars map[string]any) string {
+	return `{"data":{"event":{"id":"42","uuid":"` + vars["uuid"].(string) + `"}}}`
+}
+
 // --- NewClient ---
 
 func TestNewClient_EmptyClientID(t *testing.T) {
@@ -100,6 +149,35 @@ func TestSaveLoadToken_RoundTrip(t *testing.T) {
 	}
 }
 
+func TestSaveLoadToken_Scopes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+
+	c := clientWithMock(nil)
+	c.token = &oauth2.Token{AccessToken: "access-abc"}
+	c.scopes = []string{"write:event:create", "write:media"}
+	if err := c.SaveToken(path); err != nil {
+		t.Fatalf("SaveToken: %v", err)
+	}
+
+	c2 := clientWithMock(nil)
+	if err := c2.LoadToken(path); err != nil {
+		t.Fatalf("LoadToken: %v", err)
+	}
+	missing, known := c2.MissingScopes(PublishScopes())
+	if !known || len(missing) != 1 || missing[0] != "write:event:update" {
+		t.Errorf("expected write:event:update to be missing, got %v (known %v)", missing, known)
+	}
+
+	// a token saved before scopes were recorded
+	os.WriteFile(path, []byte(`{"access_token":"old"}`), 0600)
+	if err := c2.LoadToken(path); err != nil {
+		t.Fatalf("LoadToken: %v", err)
+	}
+	if _, known := c2.MissingScopes(PublishScopes()); known || c2.token.AccessToken != "old" {
+		t.Errorf("expected unknown scopes for an old token, got known %v, token %q", known, c2.token.AccessToken)
+	}
+}
+
 func TestSaveToken_NilToken(t *testing.T) {
 	c := clientWithMock(nil)
 	err := c.SaveToken("/tmp/should-not-be-created.json")
@@ -476,19 +554,20 @@ func TestCreateEvent_Error(t *testing.T) {
 
 // --- RetryPolicy ---
 
-func TestRetryPolicy_401_ShouldRetry(t *testing.T) {
-	resp := &http.Response{Status: "401"}
+func TestRetryPolicy_401_NoRetry(t *testing.T) {
+	// the token is refreshed rather than sent again
+	resp := &http.Response{StatusCode: 401, Status: "401 Unauthorized"}
 	retry, err := RetryPolicy(context.Background(), resp, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
-	if !retry {
-		t.Error("expected retry=true for 401")
+	if retry {
+		t.Error("expected retry=false for 401")
 	}
 }
 
 func TestRetryPolicy_200_NoRetry(t *testing.T) {
-	resp := &http.Response{Status: "200"}
+	resp := &http.Response{StatusCode: 200, Status: "200 OK"}
 	retry, _ := RetryPolicy(context.Background(), resp, nil)
 	if retry {
 		t.Error("expected retry=false for 200")
@@ -496,7 +575,7 @@ func TestRetryPolicy_200_NoRetry(t *testing.T) {
 }
 
 func TestRetryPolicy_404_NoRetry(t *testing.T) {
-	resp := &http.Response{Status: "404"}
+	resp := &http.Response{StatusCode: 404, Status: "404 Not Found"}
 	retry, _ := RetryPolicy(context.Background(), resp, nil)
 	if retry {
 		t.Error("expected retry=false for 404")
@@ -504,26 +583,617 @@ func TestRetryPolicy_404_NoRetry(t *testing.T) {
 }
 
 func TestRetryPolicy_503_ShouldRetry(t *testing.T) {
-	resp := &http.Response{Status: "503"}
+	resp := &http.Response{StatusCode: 503, Status: "503 Service Unavailable"}
 	retry, _ := RetryPolicy(context.Background(), resp, nil)
 	if !retry {
 		t.Error("expected retry=true for 503")
 	}
 }
 
-func TestRetryPolicy_405_ShouldRetry(t *testing.T) {
-	resp := &http.Response{Status: "405"}
+func TestRetryPolicy_405_NoRetry(t *testing.T) {
+	resp := &http.Response{StatusCode: 405, Status: "405 Method Not Allowed"}
+	retry, _ := RetryPolicy(context.Background(), resp, nil)
+	if retry {
+		t.Error("expected retry=false for 405")
+	}
+}
+
+func TestRetryPolicy_429_ShouldRetry(t *testing.T) {
+	resp := &http.Response{StatusCode: 429, Status: "429 Too Many Requests"}
 	retry, _ := RetryPolicy(context.Background(), resp, nil)
 	if !retry {
-		t.Error("expected retry=true for 405")
+		t.Error("expected retry=true for 429")
+	}
+}
+
+func TestRetryPolicy_NoResponse(t *testing.T) {
+	// a connection refused while the server restarts
+	retry, _ := RetryPolicy(context.Background(), nil, errors.New("connection refused"))
+	if !retry {
+		t.Error("expected retry=true without a response")
+	}
+	if retry, _ := RetryPolicy(context.Background(), nil, nil); !retry {
+		t.Error("expected retry=true for a nil response")
+	}
+	if retry, _ := RetryPolicy(context.Background(), nil, ErrServerDown); retry {
+		t.Error("expected retry=false once the breaker gave up")
+	}
+}
+
+func TestRetryPolicy_Mutation(t *testing.T) {
+	ctx := withMutation(context.Background())
+	for status, want := range map[int]bool{
+		http.StatusInternalServerError: false,
+		http.StatusTooManyRequests:     false,
+		http.StatusRequestTimeout:      false,
+		http.StatusBadGateway:          true,
+		http.StatusServiceUnavailable:  true,
+		http.StatusGatewayTimeout:      true,
+	} {
+		if retry, _ := RetryPolicy(ctx, &http.Response{StatusCode: status}, nil); retry != want {
+			t.Errorf("expected retry=%v for a mutation answered with %d", want, status)
+		}
+	}
+	refused := &url.Error{Op: "Post", URL: "http://127.0.0.1/api", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+	if retry, _ := RetryPolicy(ctx, nil, refused); !retry {
+		t.Error("expected retry=true for a mutation which could not connect")
+	}
+	// the server may have received it before the connection broke
+	for _, err := range []error{
+		&url.Error{Op: "Post", URL: "http://127.0.0.1/api", Err: io.ErrUnexpectedEOF},
+		&url.Error{Op: "Post", URL: "http://127.0.0.1/api", Err: &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}},
+	} {
+		if retry, _ := RetryPolicy(ctx, nil, err); retry {
+			t.Errorf("expected retry=false for a mutation failing with %v", err)
+		}
+	}
+}
+
+// --- DeleteEvent ---
+
+func TestDeleteEvent_Success(t *testing.T) {
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"FetchEvent": fetchEventFound,
+		"DeleteEvent": func(vars map[string]any) string {
+			if vars["id"] != "42" {
+				t.Errorf("id = %v, want %q", vars["id"], "42")
+			}
+			return `{"data":{"deleteEvent":{"id":"42"}}}`
+		},
+	})
+
+	if err := c.DeleteEvent(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+}
+
+func TestDeleteEvent_NotFound(t *testing.T) {
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"FetchEvent": func(vars map[string]any) string {
+			return `{"data":{"event":null}}`
+		},
+	})
+
+	err := c.DeleteEvent(context.Background(), uuid.New())
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestDeleteEvent_GraphQLError(t *testing.T) {
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"FetchEvent": fetchEventFound,
+		"DeleteEvent": func(vars map[string]any) string {
+			return `{"data":{"deleteEvent":null},"errors":[{"message":"You don't have permission to delete this event"}]}`
+		},
+	})
+
+	if err := c.DeleteEvent(context.Background(), uuid.New()); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+// --- CancelEvent ---
+
+func TestCancelEvent_Success(t *testing.T) {
+	id := uuid.New()
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"FetchEvent": fetchEventFound,
+		"CancelEvent": func(vars map[string]any) string {
+			if vars["id"] != "42" {
+				t.Errorf("id = %v, want %q", vars["id"], "42")
+			}
+			return `{"data":{"updateEvent":{"id":"42","uuid":"` + id.String() + `","status":"CANCELLED"}}}`
+		},
+	})
+
+	if err := c.CancelEvent(context.Background(), id); err != nil {
+		t.Fatalf("CancelEvent: %v", err)
+	}
+}
+
+func TestCancelEvent_NotCancelled(t *testing.T) {
+	id := uuid.New()
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"FetchEvent": fetchEventFound,
+		"CancelEvent": func(vars map[string]any) string {
+			return `{"data":{"updateEvent":{"id":"42","uuid":"` + id.String() + `","status":"CONFIRMED"}}}`
+		},
+	})
+
+	if err := c.CancelEvent(context.Background(), id); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestCancelEvent_FetchError(t *testing.T) {
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"FetchEvent": func(vars map[string]any) string {
+			return `{"data":null,"errors":[{"message":"Event not found"}]}`
+		},
+	})
+
+	if err := c.CancelEvent(context.Background(), uuid.New()); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+// --- FetchEvent ---
+
+func TestFetchEvent_MapsFields(t *testing.T) {
+	id := uuid.New()
+	pictureID := uuid.New()
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"FetchEvent": func(vars map[string]any) string {
+			if vars["uuid"] != id.String() {
+				t.Errorf("uuid = %v, want %v", vars["uuid"], id)
+			}
+			return `{"data":{"event":{
+				"id": "42",
+				"uuid": "` + id.String() + `",
+				"url": "https://mob.example.com/events/` + id.String() + `",
+				"local": true,
+				"title": "Test Concert",
+				"description": "A great show",
+				"beginsOn": "2025-12-31T20:00:00Z",
+				"endsOn": "2025-12-31T22:00:00Z",
+				"status": "CONFIRMED",
+				"visibility": "PUBLIC",
+				"joinOptions": "EXTERNAL",
+				"externalParticipationUrl": "https://venue.example.com/show",
+				"draft": false,
+				"category": "MUSIC",
+				"picture": {"uuid": "` + pictureID.String() + `", "url": "https://mob.example.com/media/pic.jpg", "name": "promotional image",
+					"metadata": {"width": 600, "height": 400, "blurhash": "LEHV6n"}},
+				"physicalAddress": {"id": "7", "description": "Test Venue", "street": "1 Main St", "locality": "Lausanne", "country": "Switzerland"},
+				"organizerActor": {"__typename": "Person", "id": "65691", "preferredUsername": "bot", "name": "Bot", "type": "PERSON"},
+				"attributedTo": {"__typename": "Group", "id": "73091", "preferredUsername": "venues", "name": "Venues", "type": "GROUP"},
+				"contacts": [],
+				"participantStats": {"going": 3, "notApproved": 1, "participant": 2},
+				"tags": [{"id": "1", "slug": "test-venue", "title": "Test Venue"}, {"id": "2", "slug": "lausanne", "title": "Lausanne"}],
+				"options": {"timezone": "Europe/Zurich", "showStartTime": true, "offers": [{"price": 25, "priceCurrency": "CHF"}]},
+				"metadata": [{"key": "mz:source", "title": "Source", "value": "concertcloud", "type": "STRING"}]
+			}}}`
+		},
+	})
+
+	e, err := c.FetchEvent(context.Background(), id)
+	if err != nil {
+		t.Fatalf("FetchEvent: %v", err)
+	}
+	if e.ID != "42" || e.UUID != id || e.Title != "Test Concert" {
+		t.Errorf("event mismatch: %+v", e)
+	}
+	if !e.BeginsOn.Equal(time.Date(2025, 12, 31, 20, 0, 0, 0, time.UTC)) {
+		t.Errorf("BeginsOn = %v", e.BeginsOn)
+	}
+	if e.Status != EventStatusConfirmed || e.Category != "MUSIC" {
+		t.Errorf("Status = %q, Category = %q", e.Status, e.Category)
+	}
+	if e.Picture == nil || e.Picture.UUID != pictureID || e.Picture.Width != 600 {
+		t.Errorf("Picture = %+v", e.Picture)
+	}
+	if e.PhysicalAddress == nil || e.PhysicalAddress.Street != "1 Main St" || e.PhysicalAddress.Locality != "Lausanne" {
+		t.Errorf("PhysicalAddress = %+v", e.PhysicalAddress)
+	}
+	if e.Organizer == nil || e.Organizer.ID != "65691" {
+		t.Errorf("Organizer = %+v", e.Organizer)
+	}
+	if e.AttributedTo == nil || e.AttributedTo.ID != "73091" || e.AttributedTo.PreferredUsername != "venues" {
+		t.Errorf("AttributedTo = %+v", e.AttributedTo)
+	}
+	if len(e.Tags) != 2 || e.Tags[0] != "Test Venue" || e.Tags[1] != "Lausanne" {
+		t.Errorf("Tags = %v", e.Tags)
+	}
+	if e.Options.Timezone != "Europe/Zurich" || len(e.Options.Offers) != 1 || e.Options.Offers[0].Price != 25 {
+		t.Errorf("Options = %+v", e.Options)
+	}
+	if len(e.Metadata) != 1 || e.Metadata[0].Value != "concertcloud" {
+		t.Errorf("Metadata = %+v", e.Metadata)
+	}
+	if e.ParticipantStats.Going != 3 {
+		t.Errorf("ParticipantStats = %+v", e.ParticipantStats)
+	}
+}
+
+func TestFetchEvent_NotFound(t *testing.T) {
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"FetchEvent": func(vars map[string]any) string {
+			return `{"data":{"event":null}}`
+		},
+	})
+
+	if _, err := c.FetchEvent(context.Background(), uuid.New()); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+// --- PatchEvent ---
+
+func TestPatchEvent_SendsOnlyChangedFields(t *testing.T) {
+	id := uuid.New()
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"FetchEvent": fetchEventFound,
+		"PatchEvent": func(vars map[string]any) string {
+			if vars["id"] != "42" {
+				t.Errorf("id = %v, want %q", vars["id"], "42")
+			}
+			if vars["title"] != "New Title" {
+				t.Errorf("title = %v, want %q", vars["title"], "New Title")
+			}
+			for _, name := range []string{"description", "beginsOn", "endsOn", "tags", "picture", "category", "physicalAddress"} {
+				if _, ok := vars[name]; ok {
+					t.Errorf("unchanged variable %q was sent", name)
+				}
+			}
+			return `{"data":{"updateEvent":{"id":"42","uuid":"` + id.String() + `"}}}`
+		},
+	})
+
+	uid, err := c.PatchEvent(context.Background(), EventPatch{UUID: id, Title: strPtr("New Title")})
+	if err != nil {
+		t.Fatalf("PatchEvent: %v", err)
+	}
+	if uid == nil || *uid != id {
+		t.Errorf("UUID = %v, want %v", uid, id)
+	}
+}
+
+func TestPatchEvent_Error(t *testing.T) {
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"FetchEvent": fetchEventFound,
+		"PatchEvent": func(vars map[string]any) string {
+			return `{"data":{"updateEvent":null},"errors":[{"message":"invalid title"}]}`
+		},
+	})
+
+	if _, err := c.PatchEvent(context.Background(), EventPatch{UUID: uuid.New(), Title: strPtr("")}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+// --- media cache ---
+
+// mediaServer serves a picture with an ETag, and counts the times it was
+// downloaded and the uploads sent to it
+func mediaServer(t *testing.T, picture *string, downloads *int, uploads *int) (*Client, string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/poster.png", "/other.png":
+			etag := `"` + contentHash([]byte(*picture)) + `"`
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			*downloads++
+			w.Header().Set("ETag", etag)
+			w.Write([]byte(*picture))
+		case "/api":
+			*uploads++
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{"uploadMedia":{"id":"7","uuid":"` + uuid.New().String() + `"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c := &Client{
+		baseURL:      server.URL,
+		oauth2Config: &oauth2.Config{},
+		token:        &oauth2.Token{AccessToken: "test"},
+		media:        NewMediaCache(),
+	}
+	return c, server.URL
+}
+
+func TestUploadImage_ReusesSamePicture(t *testing.T) {
+	picture, downloads, uploads := "poster", 0, 0
+	c, url := mediaServer(t, &picture, &downloads, &uploads)
+
+	first, err := c.uploadImage(context.Background(), url+"/poster.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, err := c.uploadImage(context.Background(), url+"/poster.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the same picture behind another URL is not uploaded either
+	other, err := c.uploadImage(context.Background(), url+"/other.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if uploads != 1 {
+		t.Errorf("expected 1 upload, got %d", uploads)
+	}
+	// the unchanged picture behind a cached URL is not downloaded again
+	if downloads != 2 {
+		t.Errorf("expected 2 downloads, got %d", downloads)
+	}
+	if *first != *again || *first != *other {
+		t.Errorf("expected the same media UUID, got %s, %s and %s", first, again, other)
+	}
+}
+
+func TestUploadImage_ChangedPicture(t *testing.T) {
+	picture, downloads, uploads := "poster", 0, 0
+	c, url := mediaServer(t, &picture, &downloads, &uploads)
+
+	first, err := c.uploadImage(context.Background(), url+"/poster.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	picture = "new poster"
+	second, err := c.uploadImage(context.Background(), url+"/poster.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if uploads != 2 {
+		t.Errorf("expected 2 uploads, got %d", uploads)
+	}
+	if *first == *second {
+		t.Error("expected a new media UUID for the changed picture")
+	}
+	if got := c.media.URLs[url+"/poster.png"]; got.Hash != contentHash([]byte("new poster")) || got.ETag == "" {
+		t.Errorf("expected the URL to point at the new picture, got %+v", got)
+	}
+}
+
+func TestMediaCache_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "media.json")
+	m := NewMediaCache()
+	e := MediaEntry{ID: "7", UUID: uuid.New(), Hash: contentHash([]byte("poster")), Uploaded: time.Now().UTC()}
+	src := MediaSource{Hash: e.Hash, ETag: `"poster"`}
+	m.store("https://example.com/poster.png", src, e)
+	m.use(e.UUID, uuid.New())
+
+	if err := m.Save(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, err := LoadMediaCache(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gotSrc, got, ok := loaded.source("https://example.com/poster.png")
+	if !ok || gotSrc != src || got.UUID != e.UUID || len(got.Events) != 1 {
+		t.Errorf("expected the cached picture back, got %+v", got)
+	}
+
+	missing, err := LoadMediaCache(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || len(missing.Media) != 0 {
+		t.Errorf("expected an empty cache for a missing file, got %+v, %v", missing, err)
 	}
 }
 
-// --- ErrorBackoff ---
+func TestOrphanedMedia(t *testing.T) {
+	kept, replaced, gone := uuid.New(), uuid.New(), uuid.New()
+	keptPic, replacedPic, unusedPic := uuid.New(), uuid.New(), uuid.New()
+
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"FetchEvent": func(vars map[string]any) string {
+			switch vars["uuid"].(string) {
+			case kept.String():
+				return `{"data":{"event":{"id":"1","uuid":"` + kept.String() + `","picture":{"uuid":"` + keptPic.String() + `"}}}}`
+			case replaced.String():
+				return `{"data":{"event":{"id":"2","uuid":"` + replaced.String() + `","picture":{"uuid":"` + uuid.New().String() + `"}}}}`
+			}
+			return `{"data":{"event":null},"errors":[{"message":"Event not found"}]}`
+		},
+	})
+	c.media = NewMediaCache()
+	c.media.store("a", MediaSource{Hash: "a"}, MediaEntry{UUID: keptPic, Hash: "a", Events: []uuid.UUID{kept}})
+	c.media.store("b", MediaSource{Hash: "b"}, MediaEntry{UUID: replacedPic, Hash: "b", Events: []uuid.UUID{replaced, gone}})
+	c.media.store("c", MediaSource{Hash: "c"}, MediaEntry{UUID: unusedPic, Hash: "c"})
+
+	orphans, err := c.OrphanedMedia(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(orphans) != 2 {
+		t.Fatalf("expected 2 orphans, got %+v", orphans)
+	}
+	for _, o := range orphans {
+		if o.UUID == keptPic {
+			t.Error("the picture still in use was reported as orphaned")
+		}
+	}
+}
+
+func TestRemoveMedia(t *testing.T) {
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"RemoveMedia": func(vars map[string]any) string {
+			return `{"data":{"removeMedia":{"id":"` + vars["id"].(string) + `"}}}`
+		},
+	})
+	if err := c.RemoveMedia(context.Background(), "7"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// --- SearchEventPage ---
+
+func TestSearchEventPage(t *testing.T) {
+	id := uuid.New()
+	var sent map[string]any
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"SearchEventsNear": func(vars map[string]any) string {
+			sent = vars
+			return `{"data":{"searchEvents":{"total":3,"elements":[{
+				"id":"9","uuid":"` + id.String() + `","url":"https://mobilizon.example/events/` + id.String() + `",
+				"local":true,"title":"Concert","beginsOn":"2026-11-01T19:00:00Z","category":"MUSIC",
+				"physicalAddress":{"description":"Salle","locality":"Lausanne","geom":"6.63;46.52"}
+			}]}}}`
+		},
+	})
+
+	events, total, err := c.SearchEventPage(context.Background(), SearchParams{Location: "u0k8", Radius: 10, Page: 2, Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 3 || len(events) != 1 {
+		t.Fatalf("expected 1 of 3 events, got %d of %d", len(events), total)
+	}
+	e := events[0]
+	if !e.Local || e.URL == "" || e.PhysicalAddress == nil || e.PhysicalAddress.Geom != "6.63;46.52" {
+		t.Errorf("unexpected event %+v", e)
+	}
+	if sent["location"] != "u0k8" || sent["radius"] != 10.0 || sent["page"] != 2.0 {
+		t.Errorf("unexpected variables %v", sent)
+	}
+	// filters left empty are not sent at all
+	for _, v := range []string{"term", "category", "beginsOn", "endsOn"} {
+		if _, ok := sent[v]; ok {
+			t.Errorf("expected %s to be left out, got %v", v, sent[v])
+		}
+	}
+}
+
+func TestGroupEvents(t *testing.T) {
+	var pages []float64
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"GroupEvents": func(vars map[string]any) string {
+			pages = append(pages, vars["page"].(float64))
+			if vars["id"] != "7" {
+				return `{"data":{"groupById":null}}`
+			}
+			// a full first page, then the rest
+			elements := make([]string, 50)
+			if vars["page"] == 2.0 {
+				elements = elements[:3]
+			}
+			for i := range elements {
+				elements[i] = `{"id":"1","uuid":"` + uuid.NewString() + `","title":"Concert","beginsOn":"2026-11-01T19:00:00Z"}`
+			}
+			return `{"data":{"groupById":{"id":"7","organizedEvents":{"total":53,"elements":[` + strings.Join(elements, ",") + `]}}}}`
+		},
+	})
+
+	events, err := c.GroupEvents(context.Background(), "7", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 53 || len(pages) != 2 {
+		t.Errorf("expected 53 events in 2 pages, got %d in %v", len(events), pages)
+	}
+
+	if _, err := c.GroupEvents(context.Background(), "8", time.Now()); err == nil {
+		t.Error("expected an error for an unknown group")
+	}
+}
+
+func TestResolveIdentities(t *testing.T) {
+	c := graphQLServer(t, map[string]func(map[string]any) string{
+		"Identities": func(vars map[string]any) string {
+			return `{"data":{"identities":[
+				{"id":"11","preferredUsername":"bot","name":"Bot","domain":null,"memberships":{"total":0,"elements":[]}},
+				{"id":"12","preferredUsername":"venue","name":"Venue","domain":null,"memberships":{"total":1,"elements":[
+					{"id":"5","role":"MODERATOR","parent":{"id":"73","preferredUsername":"concerts","name":"Concerts","domain":"other.example"}}
+				]}}
+			]}}`
+		},
+	})
+
+	identities, err := c.Identities(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(identities) != 2 || len(identities[1].Memberships) != 1 || identities[1].Memberships[0].Role != MemberRoleEnumModerator {
+		t.Fatalf("unexpected identities %+v", identities)
+	}
+	if u := identities[1].Memberships[0].Group.Username(); u != "@concerts@other.example" {
+		t.Errorf("expected @concerts@other.example, got %s", u)
+	}
+
+	tests := []struct {
+		username string
+		group    bool
+		want     string
+	}{
+		{"venue", false, "12"},
+		{"@venue", false, "12"},
+		{"@venue@127.0.0.1", false, "12"},
+		{"@venue@other.example", false, ""},
+		{"@concerts@other.example", true, "73"},
+		{"@concerts", true, "73"},
+		{"@bot", true, ""},
+	}
+	for _, tt := range tests {
+		resolve := c.ResolveActor
+		if tt.group {
+			resolve = c.ResolveGroup
+		}
+		got, err := resolve(context.Background(), tt.username)
+		if got != tt.want || (err == nil) != (tt.want != "") {
+			t.Errorf("resolving %s: got %q, %v, expected %q", tt.username, got, err, tt.want)
+		}
+	}
+}
+
+func TestCheckAttribution(t *testing.T) {
+	group := Actor{ID: "73", PreferredUsername: "concerts"}
+	identities := []Identity{
+		{Actor: Actor{ID: "11", PreferredUsername: "bot"}, Memberships: []Membership{{Role: MemberRoleEnumMember, Group: group}}},
+		{Actor: Actor{ID: "12", PreferredUsername: "venue"}, Memberships: []Membership{{Role: MemberRoleEnumAdministrator, Group: group}}},
+		{Actor: Actor{ID: "13", PreferredUsername: "fan"}},
+	}
+	tests := []struct {
+		actor, group string
+		want         string
+	}{
+		{"12", "73", ""},
+		{"13", "", ""},
+		{"99", "73", "actor 99 is not one of your profiles"},
+		{"13", "73", "actor @fan is not a member of group 73 (it could be published as 12 @venue)"},
+		{"11", "73", "actor @bot is MEMBER of group @concerts"},
+	}
+	for _, tt := range tests {
+		err := CheckAttribution(identities, tt.actor, tt.group)
+		if tt.want == "" && err != nil {
+			t.Errorf("actor %s, group %s: unexpected error %v", tt.actor, tt.group, err)
+		}
+		if tt.want != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.want)) {
+			t.Errorf("actor %s, group %s: expected %q, got %v", tt.actor, tt.group, tt.want, err)
+		}
+	}
+}
 
-func TestErrorBackoff_ReturnsConstant(t *testing.T) {
-	d := ErrorBackoff(0, 0, 0, nil)
-	if d != SERVER_CRASH_WAIT_TIME {
-		t.Errorf("ErrorBackoff = %v, want %v", d, SERVER_CRASH_WAIT_TIME)
+func TestGeohash(t *testing.T) {
+	tests := []struct {
+		lat, lon  float64
+		precision int
+		want      string
+	}{
+		{57.64911, 10.40744, 11, "u4pruydqqvj"},
+		{46.5197, 6.6323, 6, "u0k8wu"},
+		{-25.382708, -49.265506, 8, "6gkzwgjz"},
+	}
+	for _, tt := range tests {
+		if got := Geohash(tt.lat, tt.lon, tt.precision); got != tt.want {
+			t.Errorf("Geohash(%v, %v) = %s, expected %s", tt.lat, tt.lon, got, tt.want)
+		}
 	}
 }
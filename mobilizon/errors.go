@@ -0,0 +1,169 @@
+package mobilizon
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GraphQLError is one entry of the errors array of a GraphQL response.
+// Besides the message Mobilizòn says what kind of error it is, the HTTP
+// status it stands for and, for invalid input, the field at fault.
+type GraphQLError struct {
+	Message    string `json:"message"`
+	Code       string `json:"code"`
+	Field      string `json:"field"`
+	Path       []any  `json:"path"`
+	StatusCode int    `json:"status_code"`
+}
+
+func (g GraphQLError) Error() string {
+	msg := g.Message
+	if g.Field != "" {
+		msg = g.Field + ": " + msg
+	}
+	if path := g.path(); path != "" {
+		msg = path + ": " + msg
+	}
+	return msg
+}
+
+// path joins the path of the error, such as createEvent.physicalAddress
+func (g GraphQLError) path() string {
+	parts := make([]string, len(g.Path))
+	for i, p := range g.Path {
+		parts[i] = fmt.Sprint(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+// RequestError is a failed request to the Mobilizòn API. Each of the error
+// types below wraps one, so that errors.As finds the details whatever the
+// kind of error.
+type RequestError struct {
+	// Operation is the GraphQL operation sent
+	Operation string
+	// StatusCode is the HTTP status of the response, 0 if there was none
+	StatusCode int
+	Errors     []GraphQLError
+	// Err is why no response came back
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	var msgs []string
+	for _, g := range e.Errors {
+		msgs = append(msgs, g.Error())
+	}
+	switch {
+	case e.Err != nil:
+		msgs = append(msgs, e.Err.Error())
+	case len(msgs) == 0:
+		msgs = append(msgs, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("%s: %d %s", e.Operation, e.StatusCode, strings.Join(msgs, "; "))
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// AuthenticationError means the access token is missing, expired or was
+// revoked. Refreshing it or authorizing again may help.
+type AuthenticationError struct{ *RequestError }
+
+func (e *AuthenticationError) Unwrap() error { return e.RequestError }
+
+// PermissionError means the actor may not do what was asked, such as
+// creating events for a group it is only a member of. Trying again won't
+// help.
+type PermissionError struct{ *RequestError }
+
+func (e *PermissionError) Unwrap() error { return e.RequestError }
+
+// ValidationError means Mobilizòn refused what was sent. Field is the
+// input field at fault, and Path where in the operation it is.
+type ValidationError struct {
+	*RequestError
+	Field string
+	Path  string
+}
+
+func (e *ValidationError) Unwrap() error { return e.RequestError }
+
+// NotFoundError means what was asked for doesn't exist, or not any more
+type NotFoundError struct{ *RequestError }
+
+func (e *NotFoundError) Unwrap() error { return e.RequestError }
+
+// UnavailableError means the server could not be reached or crashed while
+// answering. It is worth trying again once it has recovered.
+type UnavailableError struct{ *RequestError }
+
+func (e *UnavailableError) Unwrap() error { return e.RequestError }
+
+// RateLimitError means too many requests were sent. RetryAfter is how long
+// the server asked us to wait, if it said.
+type RateLimitError struct {
+	*RequestError
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Unwrap() error { return e.RequestError }
+
+// newRequestError turns a failed response into the error type for its kind.
+// Mobilizòn answers most GraphQL errors with a 200, so the status and code
+// of the errors themselves count as much as the HTTP status.
+func newRequestError(operation string, resp *http.Response, errs []GraphQLError) error {
+	e := &RequestError{Operation: operation, StatusCode: resp.StatusCode, Errors: errs}
+
+	status, code, msg := resp.StatusCode, "", ""
+	if len(errs) > 0 {
+		code, msg = strings.ToLower(errs[0].Code), strings.ToLower(errs[0].Message)
+		if status == http.StatusOK && errs[0].StatusCode != 0 {
+			status = errs[0].StatusCode
+		}
+	}
+
+	switch {
+	case status == http.StatusTooManyRequests:
+		return &RateLimitError{RequestError: e, RetryAfter: retryAfter(resp.Header.Get("Retry-After"))}
+	case status >= http.StatusInternalServerError:
+		return &UnavailableError{e}
+	case status == http.StatusUnauthorized || code == "unauthenticated":
+		return &AuthenticationError{e}
+	case status == http.StatusForbidden || code == "unauthorized" || code == "forbidden":
+		return &PermissionError{e}
+	case status == http.StatusNotFound || strings.HasSuffix(code, "not_found") || strings.Contains(msg, "not found"):
+		return &NotFoundError{e}
+	case status == http.StatusUnprocessableEntity || status == http.StatusBadRequest || code == "validation" || (len(errs) > 0 && errs[0].Field != ""):
+		v := &ValidationError{RequestError: e}
+		if len(errs) > 0 {
+			v.Field, v.Path = errs[0].Field, errs[0].path()
+		}
+		return v
+	}
+	return e
+}
+
+// notFound is the error for an operation which found nothing
+func notFound(operation string, format string, args ...any) error {
+	return &NotFoundError{&RequestError{
+		Operation:  operation,
+		StatusCode: http.StatusNotFound,
+		Errors:     []GraphQLError{{Message: fmt.Sprintf(format, args...), Code: "not_found", StatusCode: http.StatusNotFound}},
+	}}
+}
+
+// retryAfter reads a Retry-After header, given in seconds or as a date
+func retryAfter(value string) time.Duration {
+	if s, err := strconv.Atoi(value); err == nil {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
@@ -0,0 +1,90 @@
+package mobilizon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// statusServer answers every request with the given status, headers and
+// body
+func statusServer(t *testing.T, status int, header map[string]string, body string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range header {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestRequestErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header map[string]string
+		body   string
+		check  func(error) bool
+	}{
+		{"expired token", 401, nil, `{"data":null}`, is[*AuthenticationError]},
+		{"unauthenticated", 200, nil, `{"data":{"identities":null},"errors":[{"code":"unauthenticated","message":"You need to be logged in","status_code":401}]}`, is[*AuthenticationError]},
+		{"not a moderator", 200, nil, `{"data":null,"errors":[{"code":"unauthorized","message":"Profile is not moderator","status_code":403}]}`, is[*PermissionError]},
+		{"unknown event", 200, nil, `{"data":null,"errors":[{"message":"Event not found"}]}`, is[*NotFoundError]},
+		{"server crash", 502, map[string]string{"Content-Type": "text/html"}, `<html>Bad Gateway</html>`, is[*UnavailableError]},
+		{"rate limited", 429, map[string]string{"Retry-After": "30"}, ``, func(err error) bool {
+			var rl *RateLimitError
+			return errors.As(err, &rl) && rl.RetryAfter == 30*time.Second
+		}},
+		{"invalid input", 200, nil, `{"data":null,"errors":[{"code":"validation","field":"title","message":"should be at least 3 characters","path":["identities",0],"status_code":422}]}`, func(err error) bool {
+			var v *ValidationError
+			return errors.As(err, &v) && v.Field == "title" && v.Path == "identities.0"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := statusServer(t, tt.status, tt.header, tt.body)
+			c := &Client{baseURL: url, gqlClient: newAPIClient(url, http.DefaultClient)}
+			_, err := c.Identities(context.Background())
+			if !tt.check(err) {
+				t.Errorf("unexpected error %T: %v", err, err)
+			}
+			// every kind of error carries the request's details
+			var re *RequestError
+			if !errors.As(err, &re) || re.Operation != "Identities" {
+				t.Errorf("expected a RequestError for Identities, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRequestErrors_NoResponse(t *testing.T) {
+	c := &Client{gqlClient: newAPIClient("http://127.0.0.1:1/api", http.DefaultClient)}
+	if _, err := c.Identities(context.Background()); !is[*UnavailableError](err) {
+		t.Errorf("expected an UnavailableError for a server which is down, got %v", err)
+	}
+}
+
+func TestUploadMedia_Errors(t *testing.T) {
+	url := statusServer(t, 200, nil, `{"data":{"uploadMedia":null},"errors":[{"code":"validation","field":"file","message":"File too large","status_code":422}]}`)
+	c := &Client{baseURL: url, oauth2Config: &oauth2.Config{}, token: &oauth2.Token{AccessToken: "test"}}
+
+	_, _, err := c.uploadMedia(context.Background(), []byte("picture"))
+	var v *ValidationError
+	if !errors.As(err, &v) || v.Field != "file" {
+		t.Errorf("expected a ValidationError for the file, got %v", err)
+	}
+}
+
+// is reports whether err is, or wraps, an error of type T
+func is[T error](err error) bool {
+	var target T
+	return errors.As(err, &target)
+}
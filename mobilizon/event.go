@@ -0,0 +1,182 @@
+// Package mobilizon implements a Mobilizon GraphQL client for golang
+package mobilizon
+
+// deref returns the value a pointer from the generated code points to, or
+// the zero value for nil
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
+
+// optional returns a pointer to v, or nil for the zero value so that an
+// omitempty variable is left out
+func optional[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return &v
+}
+
+// eventFromFullEvent converts the generated FullEvent fragment into an
+// Event
+func eventFromFullEvent(f *FullEvent) *Event {
+	e := &Event{
+		ID:                       deref(f.Id),
+		UUID:                     deref(f.Uuid),
+		URL:                      deref(f.Url),
+		Local:                    deref(f.Local),
+		Title:                    deref(f.Title),
+		Description:              deref(f.Description),
+		BeginsOn:                 deref(f.BeginsOn),
+		EndsOn:                   deref(f.EndsOn),
+		PublishAt:                deref(f.PublishAt),
+		Status:                   deref(f.Status),
+		Visibility:               deref(f.Visibility),
+		JoinOptions:              deref(f.JoinOptions),
+		ExternalParticipationURL: deref(f.ExternalParticipationUrl),
+		Draft:                    deref(f.Draft),
+		Language:                 deref(f.Language),
+		Category:                 deref(f.Category),
+		OnlineAddress:            deref(f.OnlineAddress),
+		PhoneAddress:             deref(f.PhoneAddress),
+	}
+
+	if f.Picture != nil {
+		e.Picture = &Picture{
+			UUID: deref(f.Picture.Uuid),
+			URL:  deref(f.Picture.Url),
+			Name: deref(f.Picture.Name),
+		}
+		if m := f.Picture.Metadata; m != nil {
+			e.Picture.Width = deref(m.Width)
+			e.Picture.Height = deref(m.Height)
+			e.Picture.Blurhash = deref(m.Blurhash)
+		}
+	}
+
+	if f.PhysicalAddress != nil {
+		a := addressFromFragment(&f.PhysicalAddress.AdressFragment)
+		e.PhysicalAddress = &a
+	}
+
+	if f.OrganizerActor != nil && *f.OrganizerActor != nil {
+		e.Organizer = actorFromFragment(*f.OrganizerActor)
+	}
+
+	// only groups carry any fields in the attributedTo selection
+	if f.AttributedTo != nil {
+		if g, ok := (*f.AttributedTo).(*FullEventAttributedToGroup); ok {
+			e.AttributedTo = actorFromFragment(&g.GroupMinimalFields.ActorFragmentGroup)
+		}
+	}
+
+	for _, c := range f.Contacts {
+		if c != nil && *c != nil {
+			e.Contacts = append(e.Contacts, *actorFromFragment(*c))
+		}
+	}
+
+	for _, t := range f.Tags {
+		if t != nil && t.Title != nil {
+			e.Tags = append(e.Tags, *t.Title)
+		}
+	}
+
+	if f.Options != nil {
+		e.Options = optionsFromFragment(&f.Options.EventOptions)
+	}
+
+	for _, m := range f.Metadata {
+		if m != nil {
+			e.Metadata = append(e.Metadata, Metadata{
+				Key:   deref(m.Key),
+				Title: deref(m.Title),
+				Value: deref(m.Value),
+				Type:  deref(m.Type),
+			})
+		}
+	}
+
+	if s := f.ParticipantStats; s != nil {
+		e.ParticipantStats = ParticipantStats{
+			Going:       deref(s.Going),
+			NotApproved: deref(s.NotApproved),
+			Participant: deref(s.Participant),
+		}
+	}
+
+	return e
+}
+
+// addressFromFragment converts the generated address fragment
+func addressFromFragment(f *AdressFragment) Address {
+	return Address{
+		ID:          deref(f.Id),
+		Description: deref(f.Description),
+		Geom:        deref(f.Geom),
+		Street:      deref(f.Street),
+		Locality:    deref(f.Locality),
+		PostalCode:  deref(f.PostalCode),
+		Region:      deref(f.Region),
+		Country:     deref(f.Country),
+		Type:        deref(f.Type),
+		URL:         deref(f.Url),
+		OriginID:    deref(f.OriginId),
+		Timezone:    deref(f.Timezone),
+	}
+}
+
+// actorFromFragment converts any of the generated actor fragments
+func actorFromFragment(f ActorFragment) *Actor {
+	a := &Actor{
+		ID:                deref(f.GetId()),
+		Type:              deref(f.GetType()),
+		PreferredUsername: deref(f.GetPreferredUsername()),
+		Name:              deref(f.GetName()),
+		Domain:            deref(f.GetDomain()),
+		Summary:           deref(f.GetSummary()),
+		URL:               deref(f.GetUrl()),
+	}
+	if avatar := f.GetAvatar(); avatar != nil {
+		a.AvatarURL = deref(avatar.Url)
+	}
+	return a
+}
+
+// optionsFromFragment converts the generated event options fragment
+func optionsFromFragment(f *EventOptions) Options {
+	o := Options{
+		MaximumAttendeeCapacity:       deref(f.MaximumAttendeeCapacity),
+		RemainingAttendeeCapacity:     deref(f.RemainingAttendeeCapacity),
+		ShowRemainingAttendeeCapacity: deref(f.ShowRemainingAttendeeCapacity),
+		AnonymousParticipation:        deref(f.AnonymousParticipation),
+		HideNumberOfParticipants:      deref(f.HideNumberOfParticipants),
+		ShowStartTime:                 deref(f.ShowStartTime),
+		ShowEndTime:                   deref(f.ShowEndTime),
+		Timezone:                      deref(f.Timezone),
+		Program:                       deref(f.Program),
+		CommentModeration:             deref(f.CommentModeration),
+		ShowParticipationPrice:        deref(f.ShowParticipationPrice),
+		HideOrganizerWhenGroupEvent:   deref(f.HideOrganizerWhenGroupEvent),
+		IsOnline:                      deref(f.IsOnline),
+	}
+	for _, offer := range f.Offers {
+		if offer != nil {
+			o.Offers = append(o.Offers, Offer{
+				Price:         deref(offer.Price),
+				PriceCurrency: deref(offer.PriceCurrency),
+				URL:           deref(offer.Url),
+			})
+		}
+	}
+	for _, attendee := range f.Attendees {
+		if attendee != nil {
+			o.Attendees = append(o.Attendees, *attendee)
+		}
+	}
+	return o
+}
@@ -472,6 +472,37 @@ func (v *AdressFragment) GetTimezone() *string { return v.Timezone }
 // GetTypename returns AdressFragment.Typename, and is useful for accessing the field via an interface.
 func (v *AdressFragment) GetTypename() *string { return v.Typename }
 
+// CancelEventResponse is returned by CancelEvent on success.
+type CancelEventResponse struct {
+	// Update an event
+	UpdateEvent *CancelEventUpdateEvent `json:"updateEvent"`
+}
+
+// GetUpdateEvent returns CancelEventResponse.UpdateEvent, and is useful for accessing the field via an interface.
+func (v *CancelEventResponse) GetUpdateEvent() *CancelEventUpdateEvent { return v.UpdateEvent }
+
+// CancelEventUpdateEvent includes the requested fields of the GraphQL type Event.
+// The GraphQL type's documentation follows.
+//
+// An event
+type CancelEventUpdateEvent struct {
+	// Internal ID for this event
+	Id *string `json:"id"`
+	// The Event UUID
+	Uuid *uuid.UUID `json:"uuid"`
+	// Status of the event
+	Status *EventStatus `json:"status"`
+}
+
+// GetId returns CancelEventUpdateEvent.Id, and is useful for accessing the field via an interface.
+func (v *CancelEventUpdateEvent) GetId() *string { return v.Id }
+
+// GetUuid returns CancelEventUpdateEvent.Uuid, and is useful for accessing the field via an interface.
+func (v *CancelEventUpdateEvent) GetUuid() *uuid.UUID { return v.Uuid }
+
+// GetStatus returns CancelEventUpdateEvent.Status, and is useful for accessing the field via an interface.
+func (v *CancelEventUpdateEvent) GetStatus() *EventStatus { return v.Status }
+
 // A event contact
 type Contact struct {
 	// The Contact Actor ID
@@ -507,6 +538,28 @@ type CreateEventResponse struct {
 // GetCreateEvent returns CreateEventResponse.CreateEvent, and is useful for accessing the field via an interface.
 func (v *CreateEventResponse) GetCreateEvent() *CreateEventCreateEvent { return v.CreateEvent }
 
+// DeleteEventDeleteEventDeletedObject includes the requested fields of the GraphQL type DeletedObject.
+// The GraphQL type's documentation follows.
+//
+// A struct containing the id of the deleted object
+type DeleteEventDeleteEventDeletedObject struct {
+	Id *string `json:"id"`
+}
+
+// GetId returns DeleteEventDeleteEventDeletedObject.Id, and is useful for accessing the field via an interface.
+func (v *DeleteEventDeleteEventDeletedObject) GetId() *string { return v.Id }
+
+// DeleteEventResponse is returned by DeleteEvent on success.
+type DeleteEventResponse struct {
+	// Delete an event
+	DeleteEvent *DeleteEventDeleteEventDeletedObject `json:"deleteEvent"`
+}
+
+// GetDeleteEvent returns DeleteEventResponse.DeleteEvent, and is useful for accessing the field via an interface.
+func (v *DeleteEventResponse) GetDeleteEvent() *DeleteEventDeleteEventDeletedObject {
+	return v.DeleteEvent
+}
+
 type EventCategory string
 
 const (
@@ -3203,82 +3256,214 @@ func (v *FullEventTagsTag) __premarshalJSON() (*__premarshalFullEventTagsTag, er
 	return &retval, nil
 }
 
-// GroupMinimalFields includes the GraphQL fields of Group requested by the fragment GroupMinimalFields.
+// GroupEventsGroupByIdGroup includes the requested fields of the GraphQL type Group.
 // The GraphQL type's documentation follows.
 //
 // Represents a group of actors
-type GroupMinimalFields struct {
-	ActorFragmentGroup `json:"-"`
-	// If the actor is suspended
-	Suspended *bool `json:"suspended"`
-	// Whether the group can be found and/or promoted
-	Visibility *GroupVisibility `json:"visibility"`
-	// Whether the group is opened to all or has restricted access
-	Openness *Openness `json:"openness"`
-	// Whether the actors manually approves followers
-	ManuallyApprovesFollowers *bool `json:"manuallyApprovesFollowers"`
-	// Whether the group default is to allow participants to see one another
-	AllowSeeParticipants *bool   `json:"allowSeeParticipants"`
-	Typename             *string `json:"__typename"`
+type GroupEventsGroupByIdGroup struct {
+	// Internal ID for this group
+	Id *string `json:"id"`
+	// A list of the events this actor has organized
+	OrganizedEvents *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventList `json:"organizedEvents"`
+	Typename        *string                                                     `json:"__typename"`
 }
 
-// GetSuspended returns GroupMinimalFields.Suspended, and is useful for accessing the field via an interface.
-func (v *GroupMinimalFields) GetSuspended() *bool { return v.Suspended }
+// GetId returns GroupEventsGroupByIdGroup.Id, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroup) GetId() *string { return v.Id }
 
-// GetVisibility returns GroupMinimalFields.Visibility, and is useful for accessing the field via an interface.
-func (v *GroupMinimalFields) GetVisibility() *GroupVisibility { return v.Visibility }
+// GetOrganizedEvents returns GroupEventsGroupByIdGroup.OrganizedEvents, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroup) GetOrganizedEvents() *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventList {
+	return v.OrganizedEvents
+}
 
-// GetOpenness returns GroupMinimalFields.Openness, and is useful for accessing the field via an interface.
-func (v *GroupMinimalFields) GetOpenness() *Openness { return v.Openness }
+// GetTypename returns GroupEventsGroupByIdGroup.Typename, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroup) GetTypename() *string { return v.Typename }
 
-// GetManuallyApprovesFollowers returns GroupMinimalFields.ManuallyApprovesFollowers, and is useful for accessing the field via an interface.
-func (v *GroupMinimalFields) GetManuallyApprovesFollowers() *bool { return v.ManuallyApprovesFollowers }
+// GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventList includes the requested fields of the GraphQL type PaginatedEventList.
+// The GraphQL type's documentation follows.
+//
+// A paginated list of events
+type GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventList struct {
+	// The total number of events in the list
+	Total *int `json:"total"`
+	// A list of events
+	Elements []*GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent `json:"elements"`
+	Typename *string                                                                    `json:"__typename"`
+}
 
-// GetAllowSeeParticipants returns GroupMinimalFields.AllowSeeParticipants, and is useful for accessing the field via an interface.
-func (v *GroupMinimalFields) GetAllowSeeParticipants() *bool { return v.AllowSeeParticipants }
+// GetTotal returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventList.Total, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventList) GetTotal() *int { return v.Total }
 
-// GetTypename returns GroupMinimalFields.Typename, and is useful for accessing the field via an interface.
-func (v *GroupMinimalFields) GetTypename() *string { return v.Typename }
+// GetElements returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventList.Elements, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventList) GetElements() []*GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent {
+	return v.Elements
+}
 
-// GetId returns GroupMinimalFields.Id, and is useful for accessing the field via an interface.
-func (v *GroupMinimalFields) GetId() *string { return v.ActorFragmentGroup.Id }
+// GetTypename returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventList.Typename, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventList) GetTypename() *string {
+	return v.Typename
+}
 
-// GetAvatar returns GroupMinimalFields.Avatar, and is useful for accessing the field via an interface.
-func (v *GroupMinimalFields) GetAvatar() *ActorFragmentAvatarMedia {
-	return v.ActorFragmentGroup.Avatar
+// GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent includes the requested fields of the GraphQL type Event.
+// The GraphQL type's documentation follows.
+//
+// An event
+type GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent struct {
+	FullEvent `json:"-"`
+	Typename  *string `json:"__typename"`
 }
 
-// GetType returns GroupMinimalFields.Type, and is useful for accessing the field via an interface.
-func (v *GroupMinimalFields) GetType() *ActorType { return v.ActorFragmentGroup.Type }
+// GetTypename returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Typename, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetTypename() *string {
+	return v.Typename
+}
 
-// GetPreferredUsername returns GroupMinimalFields.PreferredUsername, and is useful for accessing the field via an interface.
-func (v *GroupMinimalFields) GetPreferredUsername() *string {
-	return v.ActorFragmentGroup.PreferredUsername
+// GetId returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Id, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetId() *string {
+	return v.FullEvent.Id
 }
 
-// GetName returns GroupMinimalFields.Name, and is useful for accessing the field via an interface.
-func (v *GroupMinimalFields) GetName() *string { return v.ActorFragmentGroup.Name }
+// GetUuid returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Uuid, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetUuid() *uuid.UUID {
+	return v.FullEvent.Uuid
+}
 
-// GetDomain returns GroupMinimalFields.Domain, and is useful for accessing the field via an interface.
-func (v *GroupMinimalFields) GetDomain() *string { return v.ActorFragmentGroup.Domain }
+// GetUrl returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Url, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetUrl() *string {
+	return v.FullEvent.Url
+}
 
-// GetSummary returns GroupMinimalFields.Summary, and is useful for accessing the field via an interface.
-func (v *GroupMinimalFields) GetSummary() *string { return v.ActorFragmentGroup.Summary }
+// GetLocal returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Local, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetLocal() *bool {
+	return v.FullEvent.Local
+}
 
-// GetUrl returns GroupMinimalFields.Url, and is useful for accessing the field via an interface.
-func (v *GroupMinimalFields) GetUrl() *string { return v.ActorFragmentGroup.Url }
+// GetTitle returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Title, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetTitle() *string {
+	return v.FullEvent.Title
+}
 
-func (v *GroupMinimalFields) UnmarshalJSON(b []byte) error {
+// GetDescription returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Description, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetDescription() *string {
+	return v.FullEvent.Description
+}
+
+// GetBeginsOn returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.BeginsOn, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetBeginsOn() *time.Time {
+	return v.FullEvent.BeginsOn
+}
+
+// GetEndsOn returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.EndsOn, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetEndsOn() *time.Time {
+	return v.FullEvent.EndsOn
+}
+
+// GetStatus returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Status, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetStatus() *EventStatus {
+	return v.FullEvent.Status
+}
+
+// GetVisibility returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Visibility, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetVisibility() *EventVisibility {
+	return v.FullEvent.Visibility
+}
+
+// GetJoinOptions returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.JoinOptions, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetJoinOptions() *EventJoinOptions {
+	return v.FullEvent.JoinOptions
+}
+
+// GetExternalParticipationUrl returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.ExternalParticipationUrl, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetExternalParticipationUrl() *string {
+	return v.FullEvent.ExternalParticipationUrl
+}
+
+// GetDraft returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Draft, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetDraft() *bool {
+	return v.FullEvent.Draft
+}
+
+// GetLanguage returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Language, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetLanguage() *string {
+	return v.FullEvent.Language
+}
+
+// GetCategory returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Category, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetCategory() *EventCategory {
+	return v.FullEvent.Category
+}
+
+// GetPicture returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Picture, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetPicture() *FullEventPictureMedia {
+	return v.FullEvent.Picture
+}
+
+// GetPublishAt returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.PublishAt, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetPublishAt() *time.Time {
+	return v.FullEvent.PublishAt
+}
+
+// GetOnlineAddress returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.OnlineAddress, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetOnlineAddress() *string {
+	return v.FullEvent.OnlineAddress
+}
+
+// GetPhoneAddress returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.PhoneAddress, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetPhoneAddress() *string {
+	return v.FullEvent.PhoneAddress
+}
+
+// GetPhysicalAddress returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.PhysicalAddress, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetPhysicalAddress() *FullEventPhysicalAddress {
+	return v.FullEvent.PhysicalAddress
+}
+
+// GetOrganizerActor returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.OrganizerActor, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetOrganizerActor() *FullEventOrganizerActor {
+	return v.FullEvent.OrganizerActor
+}
+
+// GetContacts returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Contacts, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetContacts() []*FullEventContactsActor {
+	return v.FullEvent.Contacts
+}
+
+// GetAttributedTo returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.AttributedTo, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetAttributedTo() *FullEventAttributedToActor {
+	return v.FullEvent.AttributedTo
+}
+
+// GetParticipantStats returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.ParticipantStats, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetParticipantStats() *FullEventParticipantStats {
+	return v.FullEvent.ParticipantStats
+}
+
+// GetTags returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Tags, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetTags() []*FullEventTagsTag {
+	return v.FullEvent.Tags
+}
+
+// GetOptions returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Options, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetOptions() *FullEventOptions {
+	return v.FullEvent.Options
+}
+
+// GetMetadata returns GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.Metadata, and is useful for accessing the field via an interface.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) GetMetadata() []*FullEventMetadata {
+	return v.FullEvent.Metadata
+}
+
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) UnmarshalJSON(b []byte) error {
 
 	if string(b) == "null" {
 		return nil
 	}
 
 	var firstPass struct {
-		*GroupMinimalFields
+		*GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent
 		graphql.NoUnmarshalJSON
 	}
-	firstPass.GroupMinimalFields = v
+	firstPass.GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent = v
 
 	err := json.Unmarshal(b, &firstPass)
 	if err != nil {
@@ -3286,118 +3471,565 @@ func (v *GroupMinimalFields) UnmarshalJSON(b []byte) error {
 	}
 
 	err = json.Unmarshal(
-		b, &v.ActorFragmentGroup)
+		b, &v.FullEvent)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-type __premarshalGroupMinimalFields struct {
-	Suspended *bool `json:"suspended"`
+type __premarshalGroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent struct {
+	Typename *string `json:"__typename"`
 
-	Visibility *GroupVisibility `json:"visibility"`
+	Id *string `json:"id"`
 
-	Openness *Openness `json:"openness"`
+	Uuid *uuid.UUID `json:"uuid"`
 
-	ManuallyApprovesFollowers *bool `json:"manuallyApprovesFollowers"`
+	Url *string `json:"url"`
 
-	AllowSeeParticipants *bool `json:"allowSeeParticipants"`
+	Local *bool `json:"local"`
 
-	Typename *string `json:"__typename"`
+	Title *string `json:"title"`
 
-	Id *string `json:"id"`
+	Description *string `json:"description"`
 
-	Avatar *ActorFragmentAvatarMedia `json:"avatar"`
+	BeginsOn *time.Time `json:"beginsOn"`
 
-	Type *ActorType `json:"type"`
+	EndsOn *time.Time `json:"endsOn"`
 
-	PreferredUsername *string `json:"preferredUsername"`
+	Status *EventStatus `json:"status"`
 
-	Name *string `json:"name"`
+	Visibility *EventVisibility `json:"visibility"`
 
-	Domain *string `json:"domain"`
+	JoinOptions *EventJoinOptions `json:"joinOptions"`
 
-	Summary *string `json:"summary"`
+	ExternalParticipationUrl *string `json:"externalParticipationUrl"`
 
-	Url *string `json:"url"`
-}
+	Draft *bool `json:"draft"`
 
-func (v *GroupMinimalFields) MarshalJSON() ([]byte, error) {
-	premarshaled, err := v.__premarshalJSON()
-	if err != nil {
-		return nil, err
-	}
-	return json.Marshal(premarshaled)
-}
+	Language *string `json:"language"`
 
-func (v *GroupMinimalFields) __premarshalJSON() (*__premarshalGroupMinimalFields, error) {
-	var retval __premarshalGroupMinimalFields
+	Category *EventCategory `json:"category"`
 
-	retval.Suspended = v.Suspended
-	retval.Visibility = v.Visibility
-	retval.Openness = v.Openness
-	retval.ManuallyApprovesFollowers = v.ManuallyApprovesFollowers
-	retval.AllowSeeParticipants = v.AllowSeeParticipants
-	retval.Typename = v.Typename
-	retval.Id = v.ActorFragmentGroup.Id
-	retval.Avatar = v.ActorFragmentGroup.Avatar
-	retval.Type = v.ActorFragmentGroup.Type
-	retval.PreferredUsername = v.ActorFragmentGroup.PreferredUsername
-	retval.Name = v.ActorFragmentGroup.Name
-	retval.Domain = v.ActorFragmentGroup.Domain
-	retval.Summary = v.ActorFragmentGroup.Summary
-	retval.Url = v.ActorFragmentGroup.Url
-	return &retval, nil
-}
+	Picture *FullEventPictureMedia `json:"picture"`
 
-// The list of visibility options for a group
-type GroupVisibility string
+	PublishAt *time.Time `json:"publishAt"`
 
-const (
-	// Publicly listed and federated
-	GroupVisibilityPublic GroupVisibility = "PUBLIC"
-	// Visible only to people with the link - or invited
-	GroupVisibilityUnlisted GroupVisibility = "UNLISTED"
-	// Visible only to people with the link - or invited
-	GroupVisibilityPrivate GroupVisibility = "PRIVATE"
-)
+	OnlineAddress *string `json:"onlineAddress"`
 
-var AllGroupVisibility = []GroupVisibility{
-	GroupVisibilityPublic,
-	GroupVisibilityUnlisted,
-	GroupVisibilityPrivate,
-}
+	PhoneAddress *string `json:"phoneAddress"`
 
-// An attached media or a link to a media
-type MediaInput struct {
-	// The UUIDID of an existing media
-	MediaUuid *uuid.UUID `json:"mediaUuid"`
-}
+	PhysicalAddress *FullEventPhysicalAddress `json:"physicalAddress"`
 
-// GetMediaUuid returns MediaInput.MediaUuid, and is useful for accessing the field via an interface.
-func (v *MediaInput) GetMediaUuid() *uuid.UUID { return v.MediaUuid }
+	OrganizerActor json.RawMessage `json:"organizerActor"`
 
-// Describes how an actor is opened to follows
-type Openness string
+	Contacts []json.RawMessage `json:"contacts"`
 
-const (
-	// The actor can only be followed by invitation
-	OpennessInviteOnly Openness = "INVITE_ONLY"
-	// The actor needs to accept the following before it's effective
-	OpennessModerated Openness = "MODERATED"
-	// The actor is open to followings
-	OpennessOpen Openness = "OPEN"
-)
+	AttributedTo json.RawMessage `json:"attributedTo"`
 
-var AllOpenness = []Openness{
-	OpennessInviteOnly,
-	OpennessModerated,
-	OpennessOpen,
+	ParticipantStats *FullEventParticipantStats `json:"participantStats"`
+
+	Tags []*FullEventTagsTag `json:"tags"`
+
+	Options *FullEventOptions `json:"options"`
+
+	Metadata []*FullEventMetadata `json:"metadata"`
 }
 
-// RefreshAuthTokensRefreshTokenRefreshedToken includes the requested fields of the GraphQL type RefreshedToken.
-// The GraphQL type's documentation follows.
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) MarshalJSON() ([]byte, error) {
+	premarshaled, err := v.__premarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(premarshaled)
+}
+
+func (v *GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent) __premarshalJSON() (*__premarshalGroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent, error) {
+	var retval __premarshalGroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent
+
+	retval.Typename = v.Typename
+	retval.Id = v.FullEvent.Id
+	retval.Uuid = v.FullEvent.Uuid
+	retval.Url = v.FullEvent.Url
+	retval.Local = v.FullEvent.Local
+	retval.Title = v.FullEvent.Title
+	retval.Description = v.FullEvent.Description
+	retval.BeginsOn = v.FullEvent.BeginsOn
+	retval.EndsOn = v.FullEvent.EndsOn
+	retval.Status = v.FullEvent.Status
+	retval.Visibility = v.FullEvent.Visibility
+	retval.JoinOptions = v.FullEvent.JoinOptions
+	retval.ExternalParticipationUrl = v.FullEvent.ExternalParticipationUrl
+	retval.Draft = v.FullEvent.Draft
+	retval.Language = v.FullEvent.Language
+	retval.Category = v.FullEvent.Category
+	retval.Picture = v.FullEvent.Picture
+	retval.PublishAt = v.FullEvent.PublishAt
+	retval.OnlineAddress = v.FullEvent.OnlineAddress
+	retval.PhoneAddress = v.FullEvent.PhoneAddress
+	retval.PhysicalAddress = v.FullEvent.PhysicalAddress
+	{
+
+		dst := &retval.OrganizerActor
+		src := v.FullEvent.OrganizerActor
+		if src != nil {
+			var err error
+			*dst, err = __marshalFullEventOrganizerActor(
+				src)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"unable to marshal GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.FullEvent.OrganizerActor: %w", err)
+			}
+		}
+	}
+	{
+
+		dst := &retval.Contacts
+		src := v.FullEvent.Contacts
+		*dst = make(
+			[]json.RawMessage,
+			len(src))
+		for i, src := range src {
+			dst := &(*dst)[i]
+			if src != nil {
+				var err error
+				*dst, err = __marshalFullEventContactsActor(
+					src)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"unable to marshal GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.FullEvent.Contacts: %w", err)
+				}
+			}
+		}
+	}
+	{
+
+		dst := &retval.AttributedTo
+		src := v.FullEvent.AttributedTo
+		if src != nil {
+			var err error
+			*dst, err = __marshalFullEventAttributedToActor(
+				src)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"unable to marshal GroupEventsGroupByIdGroupOrganizedEventsPaginatedEventListElementsEvent.FullEvent.AttributedTo: %w", err)
+			}
+		}
+	}
+	retval.ParticipantStats = v.FullEvent.ParticipantStats
+	retval.Tags = v.FullEvent.Tags
+	retval.Options = v.FullEvent.Options
+	retval.Metadata = v.FullEvent.Metadata
+	return &retval, nil
+}
+
+// GroupEventsResponse is returned by GroupEvents on success.
+type GroupEventsResponse struct {
+	// Get a group by its preferred username
+	GroupById *GroupEventsGroupByIdGroup `json:"groupById"`
+}
+
+// GetGroupById returns GroupEventsResponse.GroupById, and is useful for accessing the field via an interface.
+func (v *GroupEventsResponse) GetGroupById() *GroupEventsGroupByIdGroup { return v.GroupById }
+
+// GroupMinimalFields includes the GraphQL fields of Group requested by the fragment GroupMinimalFields.
+// The GraphQL type's documentation follows.
+//
+// Represents a group of actors
+type GroupMinimalFields struct {
+	ActorFragmentGroup `json:"-"`
+	// If the actor is suspended
+	Suspended *bool `json:"suspended"`
+	// Whether the group can be found and/or promoted
+	Visibility *GroupVisibility `json:"visibility"`
+	// Whether the group is opened to all or has restricted access
+	Openness *Openness `json:"openness"`
+	// Whether the actors manually approves followers
+	ManuallyApprovesFollowers *bool `json:"manuallyApprovesFollowers"`
+	// Whether the group default is to allow participants to see one another
+	AllowSeeParticipants *bool   `json:"allowSeeParticipants"`
+	Typename             *string `json:"__typename"`
+}
+
+// GetSuspended returns GroupMinimalFields.Suspended, and is useful for accessing the field via an interface.
+func (v *GroupMinimalFields) GetSuspended() *bool { return v.Suspended }
+
+// GetVisibility returns GroupMinimalFields.Visibility, and is useful for accessing the field via an interface.
+func (v *GroupMinimalFields) GetVisibility() *GroupVisibility { return v.Visibility }
+
+// GetOpenness returns GroupMinimalFields.Openness, and is useful for accessing the field via an interface.
+func (v *GroupMinimalFields) GetOpenness() *Openness { return v.Openness }
+
+// GetManuallyApprovesFollowers returns GroupMinimalFields.ManuallyApprovesFollowers, and is useful for accessing the field via an interface.
+func (v *GroupMinimalFields) GetManuallyApprovesFollowers() *bool { return v.ManuallyApprovesFollowers }
+
+// GetAllowSeeParticipants returns GroupMinimalFields.AllowSeeParticipants, and is useful for accessing the field via an interface.
+func (v *GroupMinimalFields) GetAllowSeeParticipants() *bool { return v.AllowSeeParticipants }
+
+// GetTypename returns GroupMinimalFields.Typename, and is useful for accessing the field via an interface.
+func (v *GroupMinimalFields) GetTypename() *string { return v.Typename }
+
+// GetId returns GroupMinimalFields.Id, and is useful for accessing the field via an interface.
+func (v *GroupMinimalFields) GetId() *string { return v.ActorFragmentGroup.Id }
+
+// GetAvatar returns GroupMinimalFields.Avatar, and is useful for accessing the field via an interface.
+func (v *GroupMinimalFields) GetAvatar() *ActorFragmentAvatarMedia {
+	return v.ActorFragmentGroup.Avatar
+}
+
+// GetType returns GroupMinimalFields.Type, and is useful for accessing the field via an interface.
+func (v *GroupMinimalFields) GetType() *ActorType { return v.ActorFragmentGroup.Type }
+
+// GetPreferredUsername returns GroupMinimalFields.PreferredUsername, and is useful for accessing the field via an interface.
+func (v *GroupMinimalFields) GetPreferredUsername() *string {
+	return v.ActorFragmentGroup.PreferredUsername
+}
+
+// GetName returns GroupMinimalFields.Name, and is useful for accessing the field via an interface.
+func (v *GroupMinimalFields) GetName() *string { return v.ActorFragmentGroup.Name }
+
+// GetDomain returns GroupMinimalFields.Domain, and is useful for accessing the field via an interface.
+func (v *GroupMinimalFields) GetDomain() *string { return v.ActorFragmentGroup.Domain }
+
+// GetSummary returns GroupMinimalFields.Summary, and is useful for accessing the field via an interface.
+func (v *GroupMinimalFields) GetSummary() *string { return v.ActorFragmentGroup.Summary }
+
+// GetUrl returns GroupMinimalFields.Url, and is useful for accessing the field via an interface.
+func (v *GroupMinimalFields) GetUrl() *string { return v.ActorFragmentGroup.Url }
+
+func (v *GroupMinimalFields) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		return nil
+	}
+
+	var firstPass struct {
+		*GroupMinimalFields
+		graphql.NoUnmarshalJSON
+	}
+	firstPass.GroupMinimalFields = v
+
+	err := json.Unmarshal(b, &firstPass)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(
+		b, &v.ActorFragmentGroup)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type __premarshalGroupMinimalFields struct {
+	Suspended *bool `json:"suspended"`
+
+	Visibility *GroupVisibility `json:"visibility"`
+
+	Openness *Openness `json:"openness"`
+
+	ManuallyApprovesFollowers *bool `json:"manuallyApprovesFollowers"`
+
+	AllowSeeParticipants *bool `json:"allowSeeParticipants"`
+
+	Typename *string `json:"__typename"`
+
+	Id *string `json:"id"`
+
+	Avatar *ActorFragmentAvatarMedia `json:"avatar"`
+
+	Type *ActorType `json:"type"`
+
+	PreferredUsername *string `json:"preferredUsername"`
+
+	Name *string `json:"name"`
+
+	Domain *string `json:"domain"`
+
+	Summary *string `json:"summary"`
+
+	Url *string `json:"url"`
+}
+
+func (v *GroupMinimalFields) MarshalJSON() ([]byte, error) {
+	premarshaled, err := v.__premarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(premarshaled)
+}
+
+func (v *GroupMinimalFields) __premarshalJSON() (*__premarshalGroupMinimalFields, error) {
+	var retval __premarshalGroupMinimalFields
+
+	retval.Suspended = v.Suspended
+	retval.Visibility = v.Visibility
+	retval.Openness = v.Openness
+	retval.ManuallyApprovesFollowers = v.ManuallyApprovesFollowers
+	retval.AllowSeeParticipants = v.AllowSeeParticipants
+	retval.Typename = v.Typename
+	retval.Id = v.ActorFragmentGroup.Id
+	retval.Avatar = v.ActorFragmentGroup.Avatar
+	retval.Type = v.ActorFragmentGroup.Type
+	retval.PreferredUsername = v.ActorFragmentGroup.PreferredUsername
+	retval.Name = v.ActorFragmentGroup.Name
+	retval.Domain = v.ActorFragmentGroup.Domain
+	retval.Summary = v.ActorFragmentGroup.Summary
+	retval.Url = v.ActorFragmentGroup.Url
+	return &retval, nil
+}
+
+// The list of visibility options for a group
+type GroupVisibility string
+
+const (
+	// Publicly listed and federated
+	GroupVisibilityPublic GroupVisibility = "PUBLIC"
+	// Visible only to people with the link - or invited
+	GroupVisibilityUnlisted GroupVisibility = "UNLISTED"
+	// Visible only to people with the link - or invited
+	GroupVisibilityPrivate GroupVisibility = "PRIVATE"
+)
+
+var AllGroupVisibility = []GroupVisibility{
+	GroupVisibilityPublic,
+	GroupVisibilityUnlisted,
+	GroupVisibilityPrivate,
+}
+
+// IdentitiesIdentitiesPerson includes the requested fields of the GraphQL type Person.
+// The GraphQL type's documentation follows.
+//
+// Represents a person identity
+type IdentitiesIdentitiesPerson struct {
+	// Internal ID for this person
+	Id *string `json:"id"`
+	// The actor's preferred username
+	PreferredUsername *string `json:"preferredUsername"`
+	// The actor's displayed name
+	Name *string `json:"name"`
+	// The actor's domain if (null if it's this instance)
+	Domain *string `json:"domain"`
+	// The list of groups this person is member of
+	Memberships *IdentitiesIdentitiesPersonMembershipsPaginatedMemberList `json:"memberships"`
+	Typename    *string                                                   `json:"__typename"`
+}
+
+// GetId returns IdentitiesIdentitiesPerson.Id, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPerson) GetId() *string { return v.Id }
+
+// GetPreferredUsername returns IdentitiesIdentitiesPerson.PreferredUsername, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPerson) GetPreferredUsername() *string { return v.PreferredUsername }
+
+// GetName returns IdentitiesIdentitiesPerson.Name, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPerson) GetName() *string { return v.Name }
+
+// GetDomain returns IdentitiesIdentitiesPerson.Domain, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPerson) GetDomain() *string { return v.Domain }
+
+// GetMemberships returns IdentitiesIdentitiesPerson.Memberships, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPerson) GetMemberships() *IdentitiesIdentitiesPersonMembershipsPaginatedMemberList {
+	return v.Memberships
+}
+
+// GetTypename returns IdentitiesIdentitiesPerson.Typename, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPerson) GetTypename() *string { return v.Typename }
+
+// IdentitiesIdentitiesPersonMembershipsPaginatedMemberList includes the requested fields of the GraphQL type PaginatedMemberList.
+// The GraphQL type's documentation follows.
+//
+// A paginated list of members
+type IdentitiesIdentitiesPersonMembershipsPaginatedMemberList struct {
+	// The total number of elements in the list
+	Total *int `json:"total"`
+	// A list of members
+	Elements []*IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMember `json:"elements"`
+	Typename *string                                                                   `json:"__typename"`
+}
+
+// GetTotal returns IdentitiesIdentitiesPersonMembershipsPaginatedMemberList.Total, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPersonMembershipsPaginatedMemberList) GetTotal() *int { return v.Total }
+
+// GetElements returns IdentitiesIdentitiesPersonMembershipsPaginatedMemberList.Elements, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPersonMembershipsPaginatedMemberList) GetElements() []*IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMember {
+	return v.Elements
+}
+
+// GetTypename returns IdentitiesIdentitiesPersonMembershipsPaginatedMemberList.Typename, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPersonMembershipsPaginatedMemberList) GetTypename() *string {
+	return v.Typename
+}
+
+// IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMember includes the requested fields of the GraphQL type Member.
+// The GraphQL type's documentation follows.
+//
+// Represents a member of a group
+type IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMember struct {
+	// The member's ID
+	Id *string `json:"id"`
+	// The role of this membership
+	Role *MemberRoleEnum `json:"role"`
+	// Of which the profile is member
+	Parent   *IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMemberParentGroup `json:"parent"`
+	Typename *string                                                                            `json:"__typename"`
+}
+
+// GetId returns IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMember.Id, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMember) GetId() *string {
+	return v.Id
+}
+
+// GetRole returns IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMember.Role, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMember) GetRole() *MemberRoleEnum {
+	return v.Role
+}
+
+// GetParent returns IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMember.Parent, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMember) GetParent() *IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMemberParentGroup {
+	return v.Parent
+}
+
+// GetTypename returns IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMember.Typename, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMember) GetTypename() *string {
+	return v.Typename
+}
+
+// IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMemberParentGroup includes the requested fields of the GraphQL type Group.
+// The GraphQL type's documentation follows.
+//
+// Represents a group of actors
+type IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMemberParentGroup struct {
+	// Internal ID for this group
+	Id *string `json:"id"`
+	// The actor's preferred username
+	PreferredUsername *string `json:"preferredUsername"`
+	// The actor's displayed name
+	Name *string `json:"name"`
+	// The actor's domain if (null if it's this instance)
+	Domain   *string `json:"domain"`
+	Typename *string `json:"__typename"`
+}
+
+// GetId returns IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMemberParentGroup.Id, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMemberParentGroup) GetId() *string {
+	return v.Id
+}
+
+// GetPreferredUsername returns IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMemberParentGroup.PreferredUsername, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMemberParentGroup) GetPreferredUsername() *string {
+	return v.PreferredUsername
+}
+
+// GetName returns IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMemberParentGroup.Name, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMemberParentGroup) GetName() *string {
+	return v.Name
+}
+
+// GetDomain returns IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMemberParentGroup.Domain, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMemberParentGroup) GetDomain() *string {
+	return v.Domain
+}
+
+// GetTypename returns IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMemberParentGroup.Typename, and is useful for accessing the field via an interface.
+func (v *IdentitiesIdentitiesPersonMembershipsPaginatedMemberListElementsMemberParentGroup) GetTypename() *string {
+	return v.Typename
+}
+
+// IdentitiesResponse is returned by Identities on success.
+type IdentitiesResponse struct {
+	// Get the persons for an user
+	Identities []*IdentitiesIdentitiesPerson `json:"identities"`
+}
+
+// GetIdentities returns IdentitiesResponse.Identities, and is useful for accessing the field via an interface.
+func (v *IdentitiesResponse) GetIdentities() []*IdentitiesIdentitiesPerson { return v.Identities }
+
+// An attached media or a link to a media
+type MediaInput struct {
+	// The UUIDID of an existing media
+	MediaUuid *uuid.UUID `json:"mediaUuid"`
+}
+
+// GetMediaUuid returns MediaInput.MediaUuid, and is useful for accessing the field via an interface.
+func (v *MediaInput) GetMediaUuid() *uuid.UUID { return v.MediaUuid }
+
+// Values for a member role
+type MemberRoleEnum string
+
+const (
+	// The member needs to be approved by the group admins
+	MemberRoleEnumNotApproved MemberRoleEnum = "NOT_APPROVED"
+	// The member has been invited
+	MemberRoleEnumInvited MemberRoleEnum = "INVITED"
+	// Regular member
+	MemberRoleEnumMember MemberRoleEnum = "MEMBER"
+	// The member is a moderator
+	MemberRoleEnumModerator MemberRoleEnum = "MODERATOR"
+	// The member is an administrator
+	MemberRoleEnumAdministrator MemberRoleEnum = "ADMINISTRATOR"
+	// The member was the creator of the group. Shouldn't be used.
+	MemberRoleEnumCreator MemberRoleEnum = "CREATOR"
+	// The member has been rejected or excluded from the group
+	MemberRoleEnumRejected MemberRoleEnum = "REJECTED"
+)
+
+var AllMemberRoleEnum = []MemberRoleEnum{
+	MemberRoleEnumNotApproved,
+	MemberRoleEnumInvited,
+	MemberRoleEnumMember,
+	MemberRoleEnumModerator,
+	MemberRoleEnumAdministrator,
+	MemberRoleEnumCreator,
+	MemberRoleEnumRejected,
+}
+
+// Describes how an actor is opened to follows
+type Openness string
+
+const (
+	// The actor can only be followed by invitation
+	OpennessInviteOnly Openness = "INVITE_ONLY"
+	// The actor needs to accept the following before it's effective
+	OpennessModerated Openness = "MODERATED"
+	// The actor is open to followings
+	OpennessOpen Openness = "OPEN"
+)
+
+var AllOpenness = []Openness{
+	OpennessInviteOnly,
+	OpennessModerated,
+	OpennessOpen,
+}
+
+// PatchEventResponse is returned by PatchEvent on success.
+type PatchEventResponse struct {
+	// Update an event
+	UpdateEvent *PatchEventUpdateEvent `json:"updateEvent"`
+}
+
+// GetUpdateEvent returns PatchEventResponse.UpdateEvent, and is useful for accessing the field via an interface.
+func (v *PatchEventResponse) GetUpdateEvent() *PatchEventUpdateEvent { return v.UpdateEvent }
+
+// PatchEventUpdateEvent includes the requested fields of the GraphQL type Event.
+// The GraphQL type's documentation follows.
+//
+// An event
+type PatchEventUpdateEvent struct {
+	// Internal ID for this event
+	Id *string `json:"id"`
+	// The Event UUID
+	Uuid *uuid.UUID `json:"uuid"`
+}
+
+// GetId returns PatchEventUpdateEvent.Id, and is useful for accessing the field via an interface.
+func (v *PatchEventUpdateEvent) GetId() *string { return v.Id }
+
+// GetUuid returns PatchEventUpdateEvent.Uuid, and is useful for accessing the field via an interface.
+func (v *PatchEventUpdateEvent) GetUuid() *uuid.UUID { return v.Uuid }
+
+// RefreshAuthTokensRefreshTokenRefreshedToken includes the requested fields of the GraphQL type RefreshedToken.
+// The GraphQL type's documentation follows.
 //
 // Token
 type RefreshAuthTokensRefreshTokenRefreshedToken struct {
@@ -3424,76 +4056,345 @@ func (v *RefreshAuthTokensResponse) GetRefreshToken() *RefreshAuthTokensRefreshT
 	return v.RefreshToken
 }
 
+// RemoveMediaRemoveMediaDeletedObject includes the requested fields of the GraphQL type DeletedObject.
+// The GraphQL type's documentation follows.
+//
+// A struct containing the id of the deleted object
+type RemoveMediaRemoveMediaDeletedObject struct {
+	Id *string `json:"id"`
+}
+
+// GetId returns RemoveMediaRemoveMediaDeletedObject.Id, and is useful for accessing the field via an interface.
+func (v *RemoveMediaRemoveMediaDeletedObject) GetId() *string { return v.Id }
+
+// RemoveMediaResponse is returned by RemoveMedia on success.
+type RemoveMediaResponse struct {
+	// Remove a media
+	RemoveMedia *RemoveMediaRemoveMediaDeletedObject `json:"removeMedia"`
+}
+
+// GetRemoveMedia returns RemoveMediaResponse.RemoveMedia, and is useful for accessing the field via an interface.
+func (v *RemoveMediaResponse) GetRemoveMedia() *RemoveMediaRemoveMediaDeletedObject {
+	return v.RemoveMedia
+}
+
 // SearchAddressResponse is returned by SearchAddress on success.
 type SearchAddressResponse struct {
 	// Search for an address
 	SearchAddress []*SearchAddressSearchAddress `json:"searchAddress"`
 }
 
-// GetSearchAddress returns SearchAddressResponse.SearchAddress, and is useful for accessing the field via an interface.
-func (v *SearchAddressResponse) GetSearchAddress() []*SearchAddressSearchAddress {
-	return v.SearchAddress
+// GetSearchAddress returns SearchAddressResponse.SearchAddress, and is useful for accessing the field via an interface.
+func (v *SearchAddressResponse) GetSearchAddress() []*SearchAddressSearchAddress {
+	return v.SearchAddress
+}
+
+// SearchAddressSearchAddress includes the requested fields of the GraphQL type Address.
+// The GraphQL type's documentation follows.
+//
+// An address object
+type SearchAddressSearchAddress struct {
+	AdressFragment `json:"-"`
+	Typename       *string `json:"__typename"`
+}
+
+// GetTypename returns SearchAddressSearchAddress.Typename, and is useful for accessing the field via an interface.
+func (v *SearchAddressSearchAddress) GetTypename() *string { return v.Typename }
+
+// GetId returns SearchAddressSearchAddress.Id, and is useful for accessing the field via an interface.
+func (v *SearchAddressSearchAddress) GetId() *string { return v.AdressFragment.Id }
+
+// GetDescription returns SearchAddressSearchAddress.Description, and is useful for accessing the field via an interface.
+func (v *SearchAddressSearchAddress) GetDescription() *string { return v.AdressFragment.Description }
+
+// GetGeom returns SearchAddressSearchAddress.Geom, and is useful for accessing the field via an interface.
+func (v *SearchAddressSearchAddress) GetGeom() *string { return v.AdressFragment.Geom }
+
+// GetStreet returns SearchAddressSearchAddress.Street, and is useful for accessing the field via an interface.
+func (v *SearchAddressSearchAddress) GetStreet() *string { return v.AdressFragment.Street }
+
+// GetLocality returns SearchAddressSearchAddress.Locality, and is useful for accessing the field via an interface.
+func (v *SearchAddressSearchAddress) GetLocality() *string { return v.AdressFragment.Locality }
+
+// GetPostalCode returns SearchAddressSearchAddress.PostalCode, and is useful for accessing the field via an interface.
+func (v *SearchAddressSearchAddress) GetPostalCode() *string { return v.AdressFragment.PostalCode }
+
+// GetRegion returns SearchAddressSearchAddress.Region, and is useful for accessing the field via an interface.
+func (v *SearchAddressSearchAddress) GetRegion() *string { return v.AdressFragment.Region }
+
+// GetCountry returns SearchAddressSearchAddress.Country, and is useful for accessing the field via an interface.
+func (v *SearchAddressSearchAddress) GetCountry() *string { return v.AdressFragment.Country }
+
+// GetType returns SearchAddressSearchAddress.Type, and is useful for accessing the field via an interface.
+func (v *SearchAddressSearchAddress) GetType() *string { return v.AdressFragment.Type }
+
+// GetUrl returns SearchAddressSearchAddress.Url, and is useful for accessing the field via an interface.
+func (v *SearchAddressSearchAddress) GetUrl() *string { return v.AdressFragment.Url }
+
+// GetOriginId returns SearchAddressSearchAddress.OriginId, and is useful for accessing the field via an interface.
+func (v *SearchAddressSearchAddress) GetOriginId() *string { return v.AdressFragment.OriginId }
+
+// GetTimezone returns SearchAddressSearchAddress.Timezone, and is useful for accessing the field via an interface.
+func (v *SearchAddressSearchAddress) GetTimezone() *string { return v.AdressFragment.Timezone }
+
+func (v *SearchAddressSearchAddress) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		return nil
+	}
+
+	var firstPass struct {
+		*SearchAddressSearchAddress
+		graphql.NoUnmarshalJSON
+	}
+	firstPass.SearchAddressSearchAddress = v
+
+	err := json.Unmarshal(b, &firstPass)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(
+		b, &v.AdressFragment)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type __premarshalSearchAddressSearchAddress struct {
+	Typename *string `json:"__typename"`
+
+	Id *string `json:"id"`
+
+	Description *string `json:"description"`
+
+	Geom *string `json:"geom"`
+
+	Street *string `json:"street"`
+
+	Locality *string `json:"locality"`
+
+	PostalCode *string `json:"postalCode"`
+
+	Region *string `json:"region"`
+
+	Country *string `json:"country"`
+
+	Type *string `json:"type"`
+
+	Url *string `json:"url"`
+
+	OriginId *string `json:"originId"`
+
+	Timezone *string `json:"timezone"`
+}
+
+func (v *SearchAddressSearchAddress) MarshalJSON() ([]byte, error) {
+	premarshaled, err := v.__premarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(premarshaled)
+}
+
+func (v *SearchAddressSearchAddress) __premarshalJSON() (*__premarshalSearchAddressSearchAddress, error) {
+	var retval __premarshalSearchAddressSearchAddress
+
+	retval.Typename = v.Typename
+	retval.Id = v.AdressFragment.Id
+	retval.Description = v.AdressFragment.Description
+	retval.Geom = v.AdressFragment.Geom
+	retval.Street = v.AdressFragment.Street
+	retval.Locality = v.AdressFragment.Locality
+	retval.PostalCode = v.AdressFragment.PostalCode
+	retval.Region = v.AdressFragment.Region
+	retval.Country = v.AdressFragment.Country
+	retval.Type = v.AdressFragment.Type
+	retval.Url = v.AdressFragment.Url
+	retval.OriginId = v.AdressFragment.OriginId
+	retval.Timezone = v.AdressFragment.Timezone
+	return &retval, nil
+}
+
+// SearchEventsNearResponse is returned by SearchEventsNear on success.
+type SearchEventsNearResponse struct {
+	// Search events
+	SearchEvents *SearchEventsNearSearchEvents `json:"searchEvents"`
+}
+
+// GetSearchEvents returns SearchEventsNearResponse.SearchEvents, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearResponse) GetSearchEvents() *SearchEventsNearSearchEvents {
+	return v.SearchEvents
+}
+
+// SearchEventsNearSearchEvents includes the requested fields of the GraphQL type Events.
+// The GraphQL type's documentation follows.
+//
+// Search events result
+type SearchEventsNearSearchEvents struct {
+	// Total elements
+	Total int `json:"total"`
+	// Event elements
+	Elements []*SearchEventsNearSearchEventsElementsEvent `json:"elements"`
+	Typename *string                                      `json:"__typename"`
+}
+
+// GetTotal returns SearchEventsNearSearchEvents.Total, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEvents) GetTotal() int { return v.Total }
+
+// GetElements returns SearchEventsNearSearchEvents.Elements, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEvents) GetElements() []*SearchEventsNearSearchEventsElementsEvent {
+	return v.Elements
+}
+
+// GetTypename returns SearchEventsNearSearchEvents.Typename, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEvents) GetTypename() *string { return v.Typename }
+
+// SearchEventsNearSearchEventsElementsEvent includes the requested fields of the GraphQL type Event.
+// The GraphQL type's documentation follows.
+//
+// An event
+type SearchEventsNearSearchEventsElementsEvent struct {
+	FullEvent `json:"-"`
+	Typename  *string `json:"__typename"`
+}
+
+// GetTypename returns SearchEventsNearSearchEventsElementsEvent.Typename, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetTypename() *string { return v.Typename }
+
+// GetId returns SearchEventsNearSearchEventsElementsEvent.Id, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetId() *string { return v.FullEvent.Id }
+
+// GetUuid returns SearchEventsNearSearchEventsElementsEvent.Uuid, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetUuid() *uuid.UUID { return v.FullEvent.Uuid }
+
+// GetUrl returns SearchEventsNearSearchEventsElementsEvent.Url, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetUrl() *string { return v.FullEvent.Url }
+
+// GetLocal returns SearchEventsNearSearchEventsElementsEvent.Local, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetLocal() *bool { return v.FullEvent.Local }
+
+// GetTitle returns SearchEventsNearSearchEventsElementsEvent.Title, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetTitle() *string { return v.FullEvent.Title }
+
+// GetDescription returns SearchEventsNearSearchEventsElementsEvent.Description, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetDescription() *string {
+	return v.FullEvent.Description
+}
+
+// GetBeginsOn returns SearchEventsNearSearchEventsElementsEvent.BeginsOn, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetBeginsOn() *time.Time {
+	return v.FullEvent.BeginsOn
+}
+
+// GetEndsOn returns SearchEventsNearSearchEventsElementsEvent.EndsOn, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetEndsOn() *time.Time { return v.FullEvent.EndsOn }
+
+// GetStatus returns SearchEventsNearSearchEventsElementsEvent.Status, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetStatus() *EventStatus {
+	return v.FullEvent.Status
+}
+
+// GetVisibility returns SearchEventsNearSearchEventsElementsEvent.Visibility, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetVisibility() *EventVisibility {
+	return v.FullEvent.Visibility
+}
+
+// GetJoinOptions returns SearchEventsNearSearchEventsElementsEvent.JoinOptions, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetJoinOptions() *EventJoinOptions {
+	return v.FullEvent.JoinOptions
+}
+
+// GetExternalParticipationUrl returns SearchEventsNearSearchEventsElementsEvent.ExternalParticipationUrl, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetExternalParticipationUrl() *string {
+	return v.FullEvent.ExternalParticipationUrl
 }
 
-// SearchAddressSearchAddress includes the requested fields of the GraphQL type Address.
-// The GraphQL type's documentation follows.
-//
-// An address object
-type SearchAddressSearchAddress struct {
-	AdressFragment `json:"-"`
-	Typename       *string `json:"__typename"`
+// GetDraft returns SearchEventsNearSearchEventsElementsEvent.Draft, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetDraft() *bool { return v.FullEvent.Draft }
+
+// GetLanguage returns SearchEventsNearSearchEventsElementsEvent.Language, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetLanguage() *string {
+	return v.FullEvent.Language
 }
 
-// GetTypename returns SearchAddressSearchAddress.Typename, and is useful for accessing the field via an interface.
-func (v *SearchAddressSearchAddress) GetTypename() *string { return v.Typename }
+// GetCategory returns SearchEventsNearSearchEventsElementsEvent.Category, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetCategory() *EventCategory {
+	return v.FullEvent.Category
+}
 
-// GetId returns SearchAddressSearchAddress.Id, and is useful for accessing the field via an interface.
-func (v *SearchAddressSearchAddress) GetId() *string { return v.AdressFragment.Id }
+// GetPicture returns SearchEventsNearSearchEventsElementsEvent.Picture, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetPicture() *FullEventPictureMedia {
+	return v.FullEvent.Picture
+}
 
-// GetDescription returns SearchAddressSearchAddress.Description, and is useful for accessing the field via an interface.
-func (v *SearchAddressSearchAddress) GetDescription() *string { return v.AdressFragment.Description }
+// GetPublishAt returns SearchEventsNearSearchEventsElementsEvent.PublishAt, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetPublishAt() *time.Time {
+	return v.FullEvent.PublishAt
+}
 
-// GetGeom returns SearchAddressSearchAddress.Geom, and is useful for accessing the field via an interface.
-func (v *SearchAddressSearchAddress) GetGeom() *string { return v.AdressFragment.Geom }
+// GetOnlineAddress returns SearchEventsNearSearchEventsElementsEvent.OnlineAddress, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetOnlineAddress() *string {
+	return v.FullEvent.OnlineAddress
+}
 
-// GetStreet returns SearchAddressSearchAddress.Street, and is useful for accessing the field via an interface.
-func (v *SearchAddressSearchAddress) GetStreet() *string { return v.AdressFragment.Street }
+// GetPhoneAddress returns SearchEventsNearSearchEventsElementsEvent.PhoneAddress, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetPhoneAddress() *string {
+	return v.FullEvent.PhoneAddress
+}
 
-// GetLocality returns SearchAddressSearchAddress.Locality, and is useful for accessing the field via an interface.
-func (v *SearchAddressSearchAddress) GetLocality() *string { return v.AdressFragment.Locality }
+// GetPhysicalAddress returns SearchEventsNearSearchEventsElementsEvent.PhysicalAddress, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetPhysicalAddress() *FullEventPhysicalAddress {
+	return v.FullEvent.PhysicalAddress
+}
 
-// GetPostalCode returns SearchAddressSearchAddress.PostalCode, and is useful for accessing the field via an interface.
-func (v *SearchAddressSearchAddress) GetPostalCode() *string { return v.AdressFragment.PostalCode }
+// GetOrganizerActor returns SearchEventsNearSearchEventsElementsEvent.OrganizerActor, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetOrganizerActor() *FullEventOrganizerActor {
+	return v.FullEvent.OrganizerActor
+}
 
-// GetRegion returns SearchAddressSearchAddress.Region, and is useful for accessing the field via an interface.
-func (v *SearchAddressSearchAddress) GetRegion() *string { return v.AdressFragment.Region }
+// GetContacts returns SearchEventsNearSearchEventsElementsEvent.Contacts, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetContacts() []*FullEventContactsActor {
+	return v.FullEvent.Contacts
+}
 
-// GetCountry returns SearchAddressSearchAddress.Country, and is useful for accessing the field via an interface.
-func (v *SearchAddressSearchAddress) GetCountry() *string { return v.AdressFragment.Country }
+// GetAttributedTo returns SearchEventsNearSearchEventsElementsEvent.AttributedTo, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetAttributedTo() *FullEventAttributedToActor {
+	return v.FullEvent.AttributedTo
+}
 
-// GetType returns SearchAddressSearchAddress.Type, and is useful for accessing the field via an interface.
-func (v *SearchAddressSearchAddress) GetType() *string { return v.AdressFragment.Type }
+// GetParticipantStats returns SearchEventsNearSearchEventsElementsEvent.ParticipantStats, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetParticipantStats() *FullEventParticipantStats {
+	return v.FullEvent.ParticipantStats
+}
 
-// GetUrl returns SearchAddressSearchAddress.Url, and is useful for accessing the field via an interface.
-func (v *SearchAddressSearchAddress) GetUrl() *string { return v.AdressFragment.Url }
+// GetTags returns SearchEventsNearSearchEventsElementsEvent.Tags, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetTags() []*FullEventTagsTag {
+	return v.FullEvent.Tags
+}
 
-// GetOriginId returns SearchAddressSearchAddress.OriginId, and is useful for accessing the field via an interface.
-func (v *SearchAddressSearchAddress) GetOriginId() *string { return v.AdressFragment.OriginId }
+// GetOptions returns SearchEventsNearSearchEventsElementsEvent.Options, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetOptions() *FullEventOptions {
+	return v.FullEvent.Options
+}
 
-// GetTimezone returns SearchAddressSearchAddress.Timezone, and is useful for accessing the field via an interface.
-func (v *SearchAddressSearchAddress) GetTimezone() *string { return v.AdressFragment.Timezone }
+// GetMetadata returns SearchEventsNearSearchEventsElementsEvent.Metadata, and is useful for accessing the field via an interface.
+func (v *SearchEventsNearSearchEventsElementsEvent) GetMetadata() []*FullEventMetadata {
+	return v.FullEvent.Metadata
+}
 
-func (v *SearchAddressSearchAddress) UnmarshalJSON(b []byte) error {
+func (v *SearchEventsNearSearchEventsElementsEvent) UnmarshalJSON(b []byte) error {
 
 	if string(b) == "null" {
 		return nil
 	}
 
 	var firstPass struct {
-		*SearchAddressSearchAddress
+		*SearchEventsNearSearchEventsElementsEvent
 		graphql.NoUnmarshalJSON
 	}
-	firstPass.SearchAddressSearchAddress = v
+	firstPass.SearchEventsNearSearchEventsElementsEvent = v
 
 	err := json.Unmarshal(b, &firstPass)
 	if err != nil {
@@ -3501,42 +4402,72 @@ func (v *SearchAddressSearchAddress) UnmarshalJSON(b []byte) error {
 	}
 
 	err = json.Unmarshal(
-		b, &v.AdressFragment)
+		b, &v.FullEvent)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-type __premarshalSearchAddressSearchAddress struct {
+type __premarshalSearchEventsNearSearchEventsElementsEvent struct {
 	Typename *string `json:"__typename"`
 
 	Id *string `json:"id"`
 
+	Uuid *uuid.UUID `json:"uuid"`
+
+	Url *string `json:"url"`
+
+	Local *bool `json:"local"`
+
+	Title *string `json:"title"`
+
 	Description *string `json:"description"`
 
-	Geom *string `json:"geom"`
+	BeginsOn *time.Time `json:"beginsOn"`
 
-	Street *string `json:"street"`
+	EndsOn *time.Time `json:"endsOn"`
 
-	Locality *string `json:"locality"`
+	Status *EventStatus `json:"status"`
 
-	PostalCode *string `json:"postalCode"`
+	Visibility *EventVisibility `json:"visibility"`
 
-	Region *string `json:"region"`
+	JoinOptions *EventJoinOptions `json:"joinOptions"`
 
-	Country *string `json:"country"`
+	ExternalParticipationUrl *string `json:"externalParticipationUrl"`
 
-	Type *string `json:"type"`
+	Draft *bool `json:"draft"`
 
-	Url *string `json:"url"`
+	Language *string `json:"language"`
 
-	OriginId *string `json:"originId"`
+	Category *EventCategory `json:"category"`
 
-	Timezone *string `json:"timezone"`
+	Picture *FullEventPictureMedia `json:"picture"`
+
+	PublishAt *time.Time `json:"publishAt"`
+
+	OnlineAddress *string `json:"onlineAddress"`
+
+	PhoneAddress *string `json:"phoneAddress"`
+
+	PhysicalAddress *FullEventPhysicalAddress `json:"physicalAddress"`
+
+	OrganizerActor json.RawMessage `json:"organizerActor"`
+
+	Contacts []json.RawMessage `json:"contacts"`
+
+	AttributedTo json.RawMessage `json:"attributedTo"`
+
+	ParticipantStats *FullEventParticipantStats `json:"participantStats"`
+
+	Tags []*FullEventTagsTag `json:"tags"`
+
+	Options *FullEventOptions `json:"options"`
+
+	Metadata []*FullEventMetadata `json:"metadata"`
 }
 
-func (v *SearchAddressSearchAddress) MarshalJSON() ([]byte, error) {
+func (v *SearchEventsNearSearchEventsElementsEvent) MarshalJSON() ([]byte, error) {
 	premarshaled, err := v.__premarshalJSON()
 	if err != nil {
 		return nil, err
@@ -3544,22 +4475,82 @@ func (v *SearchAddressSearchAddress) MarshalJSON() ([]byte, error) {
 	return json.Marshal(premarshaled)
 }
 
-func (v *SearchAddressSearchAddress) __premarshalJSON() (*__premarshalSearchAddressSearchAddress, error) {
-	var retval __premarshalSearchAddressSearchAddress
+func (v *SearchEventsNearSearchEventsElementsEvent) __premarshalJSON() (*__premarshalSearchEventsNearSearchEventsElementsEvent, error) {
+	var retval __premarshalSearchEventsNearSearchEventsElementsEvent
 
 	retval.Typename = v.Typename
-	retval.Id = v.AdressFragment.Id
-	retval.Description = v.AdressFragment.Description
-	retval.Geom = v.AdressFragment.Geom
-	retval.Street = v.AdressFragment.Street
-	retval.Locality = v.AdressFragment.Locality
-	retval.PostalCode = v.AdressFragment.PostalCode
-	retval.Region = v.AdressFragment.Region
-	retval.Country = v.AdressFragment.Country
-	retval.Type = v.AdressFragment.Type
-	retval.Url = v.AdressFragment.Url
-	retval.OriginId = v.AdressFragment.OriginId
-	retval.Timezone = v.AdressFragment.Timezone
+	retval.Id = v.FullEvent.Id
+	retval.Uuid = v.FullEvent.Uuid
+	retval.Url = v.FullEvent.Url
+	retval.Local = v.FullEvent.Local
+	retval.Title = v.FullEvent.Title
+	retval.Description = v.FullEvent.Description
+	retval.BeginsOn = v.FullEvent.BeginsOn
+	retval.EndsOn = v.FullEvent.EndsOn
+	retval.Status = v.FullEvent.Status
+	retval.Visibility = v.FullEvent.Visibility
+	retval.JoinOptions = v.FullEvent.JoinOptions
+	retval.ExternalParticipationUrl = v.FullEvent.ExternalParticipationUrl
+	retval.Draft = v.FullEvent.Draft
+	retval.Language = v.FullEvent.Language
+	retval.Category = v.FullEvent.Category
+	retval.Picture = v.FullEvent.Picture
+	retval.PublishAt = v.FullEvent.PublishAt
+	retval.OnlineAddress = v.FullEvent.OnlineAddress
+	retval.PhoneAddress = v.FullEvent.PhoneAddress
+	retval.PhysicalAddress = v.FullEvent.PhysicalAddress
+	{
+
+		dst := &retval.OrganizerActor
+		src := v.FullEvent.OrganizerActor
+		if src != nil {
+			var err error
+			*dst, err = __marshalFullEventOrganizerActor(
+				src)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"unable to marshal SearchEventsNearSearchEventsElementsEvent.FullEvent.OrganizerActor: %w", err)
+			}
+		}
+	}
+	{
+
+		dst := &retval.Contacts
+		src := v.FullEvent.Contacts
+		*dst = make(
+			[]json.RawMessage,
+			len(src))
+		for i, src := range src {
+			dst := &(*dst)[i]
+			if src != nil {
+				var err error
+				*dst, err = __marshalFullEventContactsActor(
+					src)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"unable to marshal SearchEventsNearSearchEventsElementsEvent.FullEvent.Contacts: %w", err)
+				}
+			}
+		}
+	}
+	{
+
+		dst := &retval.AttributedTo
+		src := v.FullEvent.AttributedTo
+		if src != nil {
+			var err error
+			*dst, err = __marshalFullEventAttributedToActor(
+				src)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"unable to marshal SearchEventsNearSearchEventsElementsEvent.FullEvent.AttributedTo: %w", err)
+			}
+		}
+	}
+	retval.ParticipantStats = v.FullEvent.ParticipantStats
+	retval.Tags = v.FullEvent.Tags
+	retval.Options = v.FullEvent.Options
+	retval.Metadata = v.FullEvent.Metadata
 	return &retval, nil
 }
 
@@ -5223,6 +6214,14 @@ func (v *UpdateEventUpdateEvent) GetId() *string { return v.Id }
 // GetUuid returns UpdateEventUpdateEvent.Uuid, and is useful for accessing the field via an interface.
 func (v *UpdateEventUpdateEvent) GetUuid() *uuid.UUID { return v.Uuid }
 
+// __CancelEventInput is used internally by genqlient
+type __CancelEventInput struct {
+	Id string `json:"id"`
+}
+
+// GetId returns __CancelEventInput.Id, and is useful for accessing the field via an interface.
+func (v *__CancelEventInput) GetId() string { return v.Id }
+
 // __CreateEventInput is used internally by genqlient
 type __CreateEventInput struct {
 	OrganizerActorId         string             `json:"organizerActorId"`
@@ -5299,6 +6298,14 @@ func (v *__CreateEventInput) GetOptions() *EventOptionsInput { return v.Options
 // GetContacts returns __CreateEventInput.Contacts, and is useful for accessing the field via an interface.
 func (v *__CreateEventInput) GetContacts() []*Contact { return v.Contacts }
 
+// __DeleteEventInput is used internally by genqlient
+type __DeleteEventInput struct {
+	Id string `json:"id"`
+}
+
+// GetId returns __DeleteEventInput.Id, and is useful for accessing the field via an interface.
+func (v *__DeleteEventInput) GetId() string { return v.Id }
+
 // __FetchEventInput is used internally by genqlient
 type __FetchEventInput struct {
 	Uuid uuid.UUID `json:"uuid"`
@@ -5307,6 +6314,74 @@ type __FetchEventInput struct {
 // GetUuid returns __FetchEventInput.Uuid, and is useful for accessing the field via an interface.
 func (v *__FetchEventInput) GetUuid() uuid.UUID { return v.Uuid }
 
+// __GroupEventsInput is used internally by genqlient
+type __GroupEventsInput struct {
+	Id            string     `json:"id"`
+	AfterDatetime *time.Time `json:"afterDatetime,omitempty"`
+	Page          *int       `json:"page,omitempty"`
+	Limit         *int       `json:"limit,omitempty"`
+}
+
+// GetId returns __GroupEventsInput.Id, and is useful for accessing the field via an interface.
+func (v *__GroupEventsInput) GetId() string { return v.Id }
+
+// GetAfterDatetime returns __GroupEventsInput.AfterDatetime, and is useful for accessing the field via an interface.
+func (v *__GroupEventsInput) GetAfterDatetime() *time.Time { return v.AfterDatetime }
+
+// GetPage returns __GroupEventsInput.Page, and is useful for accessing the field via an interface.
+func (v *__GroupEventsInput) GetPage() *int { return v.Page }
+
+// GetLimit returns __GroupEventsInput.Limit, and is useful for accessing the field via an interface.
+func (v *__GroupEventsInput) GetLimit() *int { return v.Limit }
+
+// __PatchEventInput is used internally by genqlient
+type __PatchEventInput struct {
+	Id                       string         `json:"id"`
+	Title                    *string        `json:"title,omitempty"`
+	Description              *string        `json:"description,omitempty"`
+	BeginsOn                 *time.Time     `json:"beginsOn,omitempty"`
+	EndsOn                   *time.Time     `json:"endsOn,omitempty"`
+	ExternalParticipationUrl *string        `json:"externalParticipationUrl,omitempty"`
+	Tags                     []*string      `json:"tags,omitempty"`
+	Picture                  *MediaInput    `json:"picture,omitempty"`
+	OnlineAddress            *string        `json:"onlineAddress,omitempty"`
+	Category                 *EventCategory `json:"category,omitempty"`
+	PhysicalAddress          *AddressInput  `json:"physicalAddress,omitempty"`
+}
+
+// GetId returns __PatchEventInput.Id, and is useful for accessing the field via an interface.
+func (v *__PatchEventInput) GetId() string { return v.Id }
+
+// GetTitle returns __PatchEventInput.Title, and is useful for accessing the field via an interface.
+func (v *__PatchEventInput) GetTitle() *string { return v.Title }
+
+// GetDescription returns __PatchEventInput.Description, and is useful for accessing the field via an interface.
+func (v *__PatchEventInput) GetDescription() *string { return v.Description }
+
+// GetBeginsOn returns __PatchEventInput.BeginsOn, and is useful for accessing the field via an interface.
+func (v *__PatchEventInput) GetBeginsOn() *time.Time { return v.BeginsOn }
+
+// GetEndsOn returns __PatchEventInput.EndsOn, and is useful for accessing the field via an interface.
+func (v *__PatchEventInput) GetEndsOn() *time.Time { return v.EndsOn }
+
+// GetExternalParticipationUrl returns __PatchEventInput.ExternalParticipationUrl, and is useful for accessing the field via an interface.
+func (v *__PatchEventInput) GetExternalParticipationUrl() *string { return v.ExternalParticipationUrl }
+
+// GetTags returns __PatchEventInput.Tags, and is useful for accessing the field via an interface.
+func (v *__PatchEventInput) GetTags() []*string { return v.Tags }
+
+// GetPicture returns __PatchEventInput.Picture, and is useful for accessing the field via an interface.
+func (v *__PatchEventInput) GetPicture() *MediaInput { return v.Picture }
+
+// GetOnlineAddress returns __PatchEventInput.OnlineAddress, and is useful for accessing the field via an interface.
+func (v *__PatchEventInput) GetOnlineAddress() *string { return v.OnlineAddress }
+
+// GetCategory returns __PatchEventInput.Category, and is useful for accessing the field via an interface.
+func (v *__PatchEventInput) GetCategory() *EventCategory { return v.Category }
+
+// GetPhysicalAddress returns __PatchEventInput.PhysicalAddress, and is useful for accessing the field via an interface.
+func (v *__PatchEventInput) GetPhysicalAddress() *AddressInput { return v.PhysicalAddress }
+
 // __RefreshAuthTokensInput is used internally by genqlient
 type __RefreshAuthTokensInput struct {
 	Rt string `json:"rt"`
@@ -5315,6 +6390,14 @@ type __RefreshAuthTokensInput struct {
 // GetRt returns __RefreshAuthTokensInput.Rt, and is useful for accessing the field via an interface.
 func (v *__RefreshAuthTokensInput) GetRt() string { return v.Rt }
 
+// __RemoveMediaInput is used internally by genqlient
+type __RemoveMediaInput struct {
+	Id string `json:"id"`
+}
+
+// GetId returns __RemoveMediaInput.Id, and is useful for accessing the field via an interface.
+func (v *__RemoveMediaInput) GetId() string { return v.Id }
+
 // __SearchAddressInput is used internally by genqlient
 type __SearchAddressInput struct {
 	Query string `json:"query"`
@@ -5335,6 +6418,42 @@ func (v *__SearchEventsInput) GetTerm() *string { return v.Term }
 // GetBeginsOn returns __SearchEventsInput.BeginsOn, and is useful for accessing the field via an interface.
 func (v *__SearchEventsInput) GetBeginsOn() *time.Time { return v.BeginsOn }
 
+// __SearchEventsNearInput is used internally by genqlient
+type __SearchEventsNearInput struct {
+	Term     *string    `json:"term,omitempty"`
+	Location *string    `json:"location,omitempty"`
+	Radius   *float64   `json:"radius,omitempty"`
+	Category *string    `json:"category,omitempty"`
+	BeginsOn *time.Time `json:"beginsOn,omitempty"`
+	EndsOn   *time.Time `json:"endsOn,omitempty"`
+	Page     *int       `json:"page,omitempty"`
+	Limit    *int       `json:"limit,omitempty"`
+}
+
+// GetTerm returns __SearchEventsNearInput.Term, and is useful for accessing the field via an interface.
+func (v *__SearchEventsNearInput) GetTerm() *string { return v.Term }
+
+// GetLocation returns __SearchEventsNearInput.Location, and is useful for accessing the field via an interface.
+func (v *__SearchEventsNearInput) GetLocation() *string { return v.Location }
+
+// GetRadius returns __SearchEventsNearInput.Radius, and is useful for accessing the field via an interface.
+func (v *__SearchEventsNearInput) GetRadius() *float64 { return v.Radius }
+
+// GetCategory returns __SearchEventsNearInput.Category, and is useful for accessing the field via an interface.
+func (v *__SearchEventsNearInput) GetCategory() *string { return v.Category }
+
+// GetBeginsOn returns __SearchEventsNearInput.BeginsOn, and is useful for accessing the field via an interface.
+func (v *__SearchEventsNearInput) GetBeginsOn() *time.Time { return v.BeginsOn }
+
+// GetEndsOn returns __SearchEventsNearInput.EndsOn, and is useful for accessing the field via an interface.
+func (v *__SearchEventsNearInput) GetEndsOn() *time.Time { return v.EndsOn }
+
+// GetPage returns __SearchEventsNearInput.Page, and is useful for accessing the field via an interface.
+func (v *__SearchEventsNearInput) GetPage() *int { return v.Page }
+
+// GetLimit returns __SearchEventsNearInput.Limit, and is useful for accessing the field via an interface.
+func (v *__SearchEventsNearInput) GetLimit() *int { return v.Limit }
+
 // __UpdateEventInput is used internally by genqlient
 type __UpdateEventInput struct {
 	Id                       string             `json:"id"`
@@ -5412,8 +6531,44 @@ func (v *__UpdateEventInput) GetPhysicalAddress() *AddressInput { return v.Physi
 // GetOptions returns __UpdateEventInput.Options, and is useful for accessing the field via an interface.
 func (v *__UpdateEventInput) GetOptions() *EventOptionsInput { return v.Options }
 
-// GetContacts returns __UpdateEventInput.Contacts, and is useful for accessing the field via an interface.
-func (v *__UpdateEventInput) GetContacts() []*Contact { return v.Contacts }
+// GetContacts returns __UpdateEventInput.Contacts, and is useful for accessing the field via an interface.
+func (v *__UpdateEventInput) GetContacts() []*Contact { return v.Contacts }
+
+// The mutation executed by CancelEvent.
+const CancelEvent_Operation = `
+mutation CancelEvent ($id: ID!) {
+	updateEvent(eventId: $id, status: CANCELLED) {
+		id
+		uuid
+		status
+	}
+}
+`
+
+func CancelEvent(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	id string,
+) (data_ *CancelEventResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "CancelEvent",
+		Query:  CancelEvent_Operation,
+		Variables: &__CancelEventInput{
+			Id: id,
+		},
+	}
+
+	data_ = &CancelEventResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
 
 // The mutation executed by CreateEvent.
 const CreateEvent_Operation = `
@@ -5484,6 +6639,40 @@ func CreateEvent(
 	return data_, err_
 }
 
+// The mutation executed by DeleteEvent.
+const DeleteEvent_Operation = `
+mutation DeleteEvent ($id: ID!) {
+	deleteEvent(eventId: $id) {
+		id
+	}
+}
+`
+
+func DeleteEvent(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	id string,
+) (data_ *DeleteEventResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "DeleteEvent",
+		Query:  DeleteEvent_Operation,
+		Variables: &__DeleteEventInput{
+			Id: id,
+		},
+	}
+
+	data_ = &DeleteEventResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
 // The query executed by FetchEvent.
 const FetchEvent_Operation = `
 query FetchEvent ($uuid: UUID!) {
@@ -5622,36 +6811,336 @@ fragment EventOptions on EventOptions {
 		url
 		__typename
 	}
-	participationConditions {
-		title
-		content
-		url
-		__typename
+	participationConditions {
+		title
+		content
+		url
+		__typename
+	}
+	attendees
+	program
+	commentModeration
+	showParticipationPrice
+	hideOrganizerWhenGroupEvent
+	isOnline
+	__typename
+}
+`
+
+func FetchEvent(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	uuid uuid.UUID,
+) (data_ *FetchEventResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "FetchEvent",
+		Query:  FetchEvent_Operation,
+		Variables: &__FetchEventInput{
+			Uuid: uuid,
+		},
+	}
+
+	data_ = &FetchEventResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
+// The query executed by GroupEvents.
+const GroupEvents_Operation = `
+query GroupEvents ($id: ID!, $afterDatetime: DateTime, $page: Int, $limit: Int) {
+	groupById(id: $id) {
+		id
+		organizedEvents(afterDatetime: $afterDatetime, page: $page, limit: $limit) {
+			total
+			elements {
+				... FullEvent
+				__typename
+			}
+			__typename
+		}
+		__typename
+	}
+}
+fragment FullEvent on Event {
+	id
+	uuid
+	url
+	local
+	title
+	description
+	beginsOn
+	endsOn
+	status
+	visibility
+	joinOptions
+	externalParticipationUrl
+	draft
+	language
+	category
+	picture {
+		uuid
+		url
+		name
+		metadata {
+			width
+			height
+			blurhash
+			__typename
+		}
+		__typename
+	}
+	publishAt
+	onlineAddress
+	phoneAddress
+	physicalAddress {
+		... AdressFragment
+		__typename
+	}
+	organizerActor {
+		... ActorFragment
+		__typename
+	}
+	contacts {
+		... ActorFragment
+		__typename
+	}
+	attributedTo {
+		... GroupMinimalFields
+		__typename
+	}
+	participantStats {
+		going
+		notApproved
+		participant
+		__typename
+	}
+	tags {
+		... TagFragment
+		__typename
+	}
+	options {
+		... EventOptions
+		__typename
+	}
+	metadata {
+		key
+		title
+		value
+		type
+		__typename
+	}
+	__typename
+}
+fragment AdressFragment on Address {
+	id
+	description
+	geom
+	street
+	locality
+	postalCode
+	region
+	country
+	type
+	url
+	originId
+	timezone
+	__typename
+}
+fragment ActorFragment on Actor {
+	id
+	avatar {
+		uuid
+		url
+		__typename
+	}
+	type
+	preferredUsername
+	name
+	domain
+	summary
+	url
+	__typename
+}
+fragment GroupMinimalFields on Group {
+	... ActorFragment
+	suspended
+	visibility
+	openness
+	manuallyApprovesFollowers
+	allowSeeParticipants
+	__typename
+}
+fragment TagFragment on Tag {
+	id
+	slug
+	title
+	__typename
+}
+fragment EventOptions on EventOptions {
+	maximumAttendeeCapacity
+	remainingAttendeeCapacity
+	showRemainingAttendeeCapacity
+	anonymousParticipation
+	hideNumberOfParticipants
+	showStartTime
+	showEndTime
+	timezone
+	offers {
+		price
+		priceCurrency
+		url
+		__typename
+	}
+	participationConditions {
+		title
+		content
+		url
+		__typename
+	}
+	attendees
+	program
+	commentModeration
+	showParticipationPrice
+	hideOrganizerWhenGroupEvent
+	isOnline
+	__typename
+}
+`
+
+func GroupEvents(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	id string,
+	afterDatetime *time.Time,
+	page *int,
+	limit *int,
+) (data_ *GroupEventsResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "GroupEvents",
+		Query:  GroupEvents_Operation,
+		Variables: &__GroupEventsInput{
+			Id:            id,
+			AfterDatetime: afterDatetime,
+			Page:          page,
+			Limit:         limit,
+		},
+	}
+
+	data_ = &GroupEventsResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
+// The query executed by Identities.
+const Identities_Operation = `
+query Identities {
+	identities {
+		id
+		preferredUsername
+		name
+		domain
+		memberships(limit: 100) {
+			total
+			elements {
+				id
+				role
+				parent {
+					id
+					preferredUsername
+					name
+					domain
+					__typename
+				}
+				__typename
+			}
+			__typename
+		}
+		__typename
+	}
+}
+`
+
+func Identities(
+	ctx_ context.Context,
+	client_ graphql.Client,
+) (data_ *IdentitiesResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "Identities",
+		Query:  Identities_Operation,
+	}
+
+	data_ = &IdentitiesResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
+// The mutation executed by PatchEvent.
+const PatchEvent_Operation = `
+mutation PatchEvent ($id: ID!, $title: String, $description: String, $beginsOn: DateTime, $endsOn: DateTime, $externalParticipationUrl: String, $tags: [String], $picture: MediaInput, $onlineAddress: String, $category: EventCategory, $physicalAddress: AddressInput) {
+	updateEvent(eventId: $id, title: $title, description: $description, beginsOn: $beginsOn, endsOn: $endsOn, externalParticipationUrl: $externalParticipationUrl, tags: $tags, picture: $picture, onlineAddress: $onlineAddress, category: $category, physicalAddress: $physicalAddress) {
+		id
+		uuid
 	}
-	attendees
-	program
-	commentModeration
-	showParticipationPrice
-	hideOrganizerWhenGroupEvent
-	isOnline
-	__typename
 }
 `
 
-func FetchEvent(
+// only the variables which are set are sent, so the rest of the event is
+// left as it is
+func PatchEvent(
 	ctx_ context.Context,
 	client_ graphql.Client,
-	uuid uuid.UUID,
-) (data_ *FetchEventResponse, err_ error) {
+	id string,
+	title *string,
+	description *string,
+	beginsOn *time.Time,
+	endsOn *time.Time,
+	externalParticipationUrl *string,
+	tags []*string,
+	picture *MediaInput,
+	onlineAddress *string,
+	category *EventCategory,
+	physicalAddress *AddressInput,
+) (data_ *PatchEventResponse, err_ error) {
 	req_ := &graphql.Request{
-		OpName: "FetchEvent",
-		Query:  FetchEvent_Operation,
-		Variables: &__FetchEventInput{
-			Uuid: uuid,
+		OpName: "PatchEvent",
+		Query:  PatchEvent_Operation,
+		Variables: &__PatchEventInput{
+			Id:                       id,
+			Title:                    title,
+			Description:              description,
+			BeginsOn:                 beginsOn,
+			EndsOn:                   endsOn,
+			ExternalParticipationUrl: externalParticipationUrl,
+			Tags:                     tags,
+			Picture:                  picture,
+			OnlineAddress:            onlineAddress,
+			Category:                 category,
+			PhysicalAddress:          physicalAddress,
 		},
 	}
 
-	data_ = &FetchEventResponse{}
+	data_ = &PatchEventResponse{}
 	resp_ := &graphql.Response{Data: data_}
 
 	err_ = client_.MakeRequest(
@@ -5698,6 +7187,40 @@ func RefreshAuthTokens(
 	return data_, err_
 }
 
+// The mutation executed by RemoveMedia.
+const RemoveMedia_Operation = `
+mutation RemoveMedia ($id: ID!) {
+	removeMedia(id: $id) {
+		id
+	}
+}
+`
+
+func RemoveMedia(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	id string,
+) (data_ *RemoveMediaResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "RemoveMedia",
+		Query:  RemoveMedia_Operation,
+		Variables: &__RemoveMediaInput{
+			Id: id,
+		},
+	}
+
+	data_ = &RemoveMediaResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
 // The query executed by SearchAddress.
 const SearchAddress_Operation = `
 query SearchAddress ($query: String!) {
@@ -5883,6 +7406,205 @@ func SearchEvents(
 	return data_, err_
 }
 
+// The query executed by SearchEventsNear.
+const SearchEventsNear_Operation = `
+query SearchEventsNear ($term: String, $location: String, $radius: Float, $category: String, $beginsOn: DateTime, $endsOn: DateTime, $page: Int, $limit: Int) {
+	searchEvents(term: $term, location: $location, radius: $radius, category: $category, beginsOn: $beginsOn, endsOn: $endsOn, page: $page, limit: $limit) {
+		total
+		elements {
+			... FullEvent
+			__typename
+		}
+		__typename
+	}
+}
+fragment FullEvent on Event {
+	id
+	uuid
+	url
+	local
+	title
+	description
+	beginsOn
+	endsOn
+	status
+	visibility
+	joinOptions
+	externalParticipationUrl
+	draft
+	language
+	category
+	picture {
+		uuid
+		url
+		name
+		metadata {
+			width
+			height
+			blurhash
+			__typename
+		}
+		__typename
+	}
+	publishAt
+	onlineAddress
+	phoneAddress
+	physicalAddress {
+		... AdressFragment
+		__typename
+	}
+	organizerActor {
+		... ActorFragment
+		__typename
+	}
+	contacts {
+		... ActorFragment
+		__typename
+	}
+	attributedTo {
+		... GroupMinimalFields
+		__typename
+	}
+	participantStats {
+		going
+		notApproved
+		participant
+		__typename
+	}
+	tags {
+		... TagFragment
+		__typename
+	}
+	options {
+		... EventOptions
+		__typename
+	}
+	metadata {
+		key
+		title
+		value
+		type
+		__typename
+	}
+	__typename
+}
+fragment AdressFragment on Address {
+	id
+	description
+	geom
+	street
+	locality
+	postalCode
+	region
+	country
+	type
+	url
+	originId
+	timezone
+	__typename
+}
+fragment ActorFragment on Actor {
+	id
+	avatar {
+		uuid
+		url
+		__typename
+	}
+	type
+	preferredUsername
+	name
+	domain
+	summary
+	url
+	__typename
+}
+fragment GroupMinimalFields on Group {
+	... ActorFragment
+	suspended
+	visibility
+	openness
+	manuallyApprovesFollowers
+	allowSeeParticipants
+	__typename
+}
+fragment TagFragment on Tag {
+	id
+	slug
+	title
+	__typename
+}
+fragment EventOptions on EventOptions {
+	maximumAttendeeCapacity
+	remainingAttendeeCapacity
+	showRemainingAttendeeCapacity
+	anonymousParticipation
+	hideNumberOfParticipants
+	showStartTime
+	showEndTime
+	timezone
+	offers {
+		price
+		priceCurrency
+		url
+		__typename
+	}
+	participationConditions {
+		title
+		content
+		url
+		__typename
+	}
+	attendees
+	program
+	commentModeration
+	showParticipationPrice
+	hideOrganizerWhenGroupEvent
+	isOnline
+	__typename
+}
+`
+
+// searches an area page by page; only the filters which are set are sent,
+// so that the server's defaults apply to the rest
+func SearchEventsNear(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	term *string,
+	location *string,
+	radius *float64,
+	category *string,
+	beginsOn *time.Time,
+	endsOn *time.Time,
+	page *int,
+	limit *int,
+) (data_ *SearchEventsNearResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "SearchEventsNear",
+		Query:  SearchEventsNear_Operation,
+		Variables: &__SearchEventsNearInput{
+			Term:     term,
+			Location: location,
+			Radius:   radius,
+			Category: category,
+			BeginsOn: beginsOn,
+			EndsOn:   endsOn,
+			Page:     page,
+			Limit:    limit,
+		},
+	}
+
+	data_ = &SearchEventsNearResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
 // The mutation executed by UpdateEvent.
 const UpdateEvent_Operation = `
 mutation UpdateEvent ($id: ID!, $title: String, $description: String, $beginsOn: DateTime, $endsOn: DateTime, $status: EventStatus, $visibility: EventVisibility, $joinOptions: EventJoinOptions, $externalParticipationUrl: String, $draft: Boolean, $tags: [String], $picture: MediaInput, $onlineAddress: String, $organizerActorId: ID, $attributedToId: ID, $category: EventCategory, $physicalAddress: AddressInput, $options: EventOptionsInput, $contacts: [Contact]) {
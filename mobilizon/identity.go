@@ -0,0 +1,173 @@
+package mobilizon
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// EventCreatorRoles are the roles in a group which may create its events
+var EventCreatorRoles = []MemberRoleEnum{
+	MemberRoleEnumModerator,
+	MemberRoleEnumAdministrator,
+	MemberRoleEnumCreator,
+}
+
+// Identities returns the profiles of the authenticated user and the groups
+// each of them is a member of
+func (c *Client) Identities(ctx context.Context) ([]Identity, error) {
+	resp, err := Identities(ctx, c.gqlClient)
+	if err != nil {
+		return nil, err
+	}
+
+	var identities []Identity
+	for _, p := range resp.Identities {
+		if p == nil {
+			continue
+		}
+		id := Identity{Actor: Actor{
+			ID:                deref(p.Id),
+			Type:              ActorTypePerson,
+			PreferredUsername: deref(p.PreferredUsername),
+			Name:              deref(p.Name),
+			Domain:            deref(p.Domain),
+		}}
+		if p.Memberships != nil {
+			for _, m := range p.Memberships.Elements {
+				if m == nil || m.Parent == nil {
+					continue
+				}
+				id.Memberships = append(id.Memberships, Membership{
+					ID:   deref(m.Id),
+					Role: deref(m.Role),
+					Group: Actor{
+						ID:                deref(m.Parent.Id),
+						Type:              ActorTypeGroup,
+						PreferredUsername: deref(m.Parent.PreferredUsername),
+						Name:              deref(m.Parent.Name),
+						Domain:            deref(m.Parent.Domain),
+					},
+				})
+			}
+		}
+		identities = append(identities, id)
+	}
+	return identities, nil
+}
+
+// Username is the federated username of an actor, @name for a local one
+// and @name@domain for a remote one
+func (a Actor) Username() string {
+	if a.Domain == "" {
+		return "@" + a.PreferredUsername
+	}
+	return "@" + a.PreferredUsername + "@" + a.Domain
+}
+
+// matches reports whether a username, written as name, @name or
+// @name@domain, is this actor's. Local actors have no domain, so they also
+// match the host of our own instance.
+func (a Actor) matches(username string, host string) bool {
+	name, domain, _ := strings.Cut(strings.TrimPrefix(username, "@"), "@")
+	if !strings.EqualFold(name, a.PreferredUsername) {
+		return false
+	}
+	switch {
+	case domain == "":
+		return true
+	case a.Domain == "":
+		return strings.EqualFold(domain, host)
+	}
+	return strings.EqualFold(domain, a.Domain)
+}
+
+// ResolveActor finds the ID of one of the authenticated user's profiles by
+// its username
+func (c *Client) ResolveActor(ctx context.Context, username string) (string, error) {
+	identities, err := c.Identities(ctx)
+	if err != nil {
+		return "", err
+	}
+	host := c.host()
+	for _, id := range identities {
+		if id.matches(username, host) {
+			return id.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not one of your profiles", username)
+}
+
+// ResolveGroup finds the ID of a group one of the authenticated user's
+// profiles is a member of by its username
+func (c *Client) ResolveGroup(ctx context.Context, username string) (string, error) {
+	identities, err := c.Identities(ctx)
+	if err != nil {
+		return "", err
+	}
+	host := c.host()
+	for _, id := range identities {
+		for _, m := range id.Memberships {
+			if m.Group.matches(username, host) {
+				return m.Group.ID, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("none of your profiles is a member of group %s", username)
+}
+
+// host is the host name of our instance
+func (c *Client) host() string {
+	if u, err := url.Parse(c.baseURL); err == nil {
+		return u.Hostname()
+	}
+	return ""
+}
+
+// CheckAttribution reports why the actor can't publish events attributed
+// to the group, if it can't: the actor must be one of the authenticated
+// user's profiles, and a member of the group allowed to create events. A
+// groupID of "" is for events published by the actor alone.
+func CheckAttribution(identities []Identity, actorID string, groupID string) error {
+	i := slices.IndexFunc(identities, func(id Identity) bool { return id.ID == actorID })
+	if i < 0 {
+		var yours []string
+		for _, id := range identities {
+			yours = append(yours, id.ID+" "+id.Username())
+		}
+		return fmt.Errorf("actor %s is not one of your profiles, which are %s", actorID, strings.Join(yours, ", "))
+	}
+	actor := identities[i]
+	if groupID == "" {
+		return nil
+	}
+
+	j := slices.IndexFunc(actor.Memberships, func(m Membership) bool { return m.Group.ID == groupID })
+	if j < 0 {
+		return fmt.Errorf("actor %s is not a member of group %s%s", actor.Username(), groupID, otherCreators(identities, groupID))
+	}
+	m := actor.Memberships[j]
+	if !slices.Contains(EventCreatorRoles, m.Role) {
+		return fmt.Errorf("actor %s is %s of group %s, creating its events needs MODERATOR or above%s", actor.Username(), m.Role, m.Group.Username(), otherCreators(identities, groupID))
+	}
+	return nil
+}
+
+// otherCreators names the profiles which could create the group's events
+// instead, as a hint
+func otherCreators(identities []Identity, groupID string) string {
+	var names []string
+	for _, id := range identities {
+		if slices.ContainsFunc(id.Memberships, func(m Membership) bool {
+			return m.Group.ID == groupID && slices.Contains(EventCreatorRoles, m.Role)
+		}) {
+			names = append(names, id.ID+" "+id.Username())
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return " (it could be published as " + strings.Join(names, " or ") + ")"
+}
@@ -0,0 +1,173 @@
+package mobilizon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MediaEntry is a picture we have uploaded to Mobilizòn
+type MediaEntry struct {
+	ID       string    `json:"id,omitempty"`
+	UUID     uuid.UUID `json:"uuid"`
+	Hash     string    `json:"hash"`
+	Uploaded time.Time `json:"uploaded"`
+	// the events we have set the picture on
+	Events []uuid.UUID `json:"events,omitempty"`
+}
+
+// MediaSource is what we know about the picture behind a source URL: the
+// hash of its content when we last downloaded it, and the validators the
+// server sent with it
+type MediaSource struct {
+	Hash         string `json:"hash"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// MediaCache remembers uploaded pictures by their source URL and the hash
+// of their content, so that the same poster is only uploaded once however
+// many events use it. A cached URL is only downloaded again when its server
+// says the picture changed, so that a new image behind an old URL is still
+// noticed and uploaded.
+type MediaCache struct {
+	// source URL -> content hash and validators
+	URLs map[string]MediaSource `json:"urls"`
+	// content hash -> uploaded media
+	Media map[string]MediaEntry `json:"media"`
+
+	mu sync.Mutex
+}
+
+// NewMediaCache creates an empty media cache
+func NewMediaCache() *MediaCache {
+	return &MediaCache{
+		URLs:  make(map[string]MediaSource),
+		Media: make(map[string]MediaEntry),
+	}
+}
+
+// LoadMediaCache reads the cache from a file. A missing file gives an
+// empty cache.
+func LoadMediaCache(path string) (*MediaCache, error) {
+	m := NewMediaCache()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	if m.URLs == nil {
+		m.URLs = make(map[string]MediaSource)
+	}
+	if m.Media == nil {
+		m.Media = make(map[string]MediaEntry)
+	}
+	return m, nil
+}
+
+// Save writes the cache to a file
+func (m *MediaCache) Save(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// source returns the validators of the URL and the media uploaded for it,
+// as long as that media is still cached
+func (m *MediaCache) source(url string) (MediaSource, MediaEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	src, ok := m.URLs[url]
+	if !ok {
+		return MediaSource{}, MediaEntry{}, false
+	}
+	e, ok := m.Media[src.Hash]
+	if !ok {
+		return MediaSource{}, MediaEntry{}, false
+	}
+	return src, e, true
+}
+
+// lookup returns the media uploaded for the downloaded content and points
+// the URL at it, replacing whatever the URL pointed at before
+func (m *MediaCache) lookup(url string, src MediaSource) (MediaEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.Media[src.Hash]
+	if ok {
+		m.URLs[url] = src
+	}
+	return e, ok
+}
+
+// Picture returns the media last uploaded for the source URL
+func (m *MediaCache) Picture(url string) (MediaEntry, bool) {
+	_, e, ok := m.source(url)
+	return e, ok
+}
+
+// store records a newly uploaded picture and the source it came from
+func (m *MediaCache) store(url string, src MediaSource, e MediaEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.URLs[url] = src
+	m.Media[e.Hash] = e
+}
+
+// use records that the picture was set on an event
+func (m *MediaCache) use(mediaUUID uuid.UUID, eventUUID uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for h, e := range m.Media {
+		if e.UUID == mediaUUID && !slices.Contains(e.Events, eventUUID) {
+			e.Events = append(e.Events, eventUUID)
+			m.Media[h] = e
+		}
+	}
+}
+
+// Entries returns every cached picture
+func (m *MediaCache) Entries() []MediaEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entries := make([]MediaEntry, 0, len(m.Media))
+	for _, e := range m.Media {
+		entries = append(entries, e)
+	}
+	slices.SortFunc(entries, func(a, b MediaEntry) int { return a.Uploaded.Compare(b.Uploaded) })
+	return entries
+}
+
+// Remove forgets a picture and every URL pointing at it
+func (m *MediaCache) Remove(hash string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.Media, hash)
+	for u, src := range m.URLs {
+		if src.Hash == hash {
+			delete(m.URLs, u)
+		}
+	}
+}
+
+func contentHash(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
@@ -23,6 +23,10 @@ import (
 const MAX_IMG_SIZE = 1024 * 800 // 800kb
 const IMAGE_RESIZE_WIDTH = 600
 
+// errNotModified is returned by downloadFile when the server says the
+// picture has not changed since it was cached
+var errNotModified = errors.New("not modified")
+
 func loadFileContents(path string) ([]byte, fs.FileInfo, error) {
 	var fileContents []byte
 	var fi fs.FileInfo
@@ -100,40 +104,52 @@ func thumbnail(r io.Reader, w io.Writer, mimetype string, width int) error {
 }
 
 // downloadFile downloads a file from a given URL and returns the local
-// file path or "" and an error or nil
-func downloadFile(URL string) (string, error) {
+// file path or "" and an error or nil. The validators of the cached source,
+// if any, make the request conditional, and errNotModified is returned when
+// the picture has not changed. The validators of the download are returned
+// with the path.
+func downloadFile(URL string, cached MediaSource) (string, MediaSource, error) {
 	// if this is a data URL just return it. The uplaod function will deal.
 	if strings.HasPrefix(URL, "data:") {
-		return URL, nil
+		return URL, MediaSource{}, nil
 	}
 
 	//Get the response bytes from the url
 	client := &http.Client{}
 	req, err := http.NewRequest("GET", strings.Split(URL, "?")[0], nil)
 	if err != nil {
-		return "", err
+		return "", MediaSource{}, err
 	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36")
+	if cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	if cached.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
 	response, err := client.Do(req)
 	if err != nil {
-		return "", err
+		return "", MediaSource{}, err
 	}
 	defer response.Body.Close()
 
+	if response.StatusCode == http.StatusNotModified {
+		return "", MediaSource{}, errNotModified
+	}
 	if response.StatusCode != 200 {
-		return "", errors.New(fmt.Sprintf("Received response code %d for %s", response.StatusCode, URL))
+		return "", MediaSource{}, errors.New(fmt.Sprintf("Received response code %d for %s", response.StatusCode, URL))
 	}
 
 	// get tmp filename
 	f, err := os.CreateTemp("", "cc2mob.")
 	if err != nil {
-		return f.Name(), err
+		return f.Name(), MediaSource{}, err
 	}
 
 	//Create a empty file
 	file, err := os.Create(f.Name())
 	if err != nil {
-		return f.Name(), err
+		return f.Name(), MediaSource{}, err
 	}
 	defer file.Close()
 
@@ -144,8 +160,12 @@ func downloadFile(URL string) (string, error) {
 		_, err = io.Copy(file, response.Body)
 	}
 	if err != nil {
-		return f.Name(), err
+		return f.Name(), MediaSource{}, err
 	}
 
-	return f.Name(), nil
+	src := MediaSource{
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+	}
+	return f.Name(), src, nil
 }
@@ -75,6 +75,18 @@ func RegisterApp(ctx context.Context, rc RegisterConfig) (*Registration, error)
 
 // DefaultScopes returns the default OAuth2 scopes for Mobilizon
 func DefaultScopes() []string {
+	return []string{
+		"write:event:create",
+		"write:event:update",
+		"write:event:delete",
+		"write:media:upload",
+		"write:media:remove",
+	}
+}
+
+// PublishScopes returns the scopes a run needs to create and update events
+// and upload their pictures
+func PublishScopes() []string {
 	return []string{
 		"write:event:create",
 		"write:event:update",
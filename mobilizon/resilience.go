@@ -0,0 +1,232 @@
+package mobilizon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// ErrServerDown is returned for requests which waited for the server to
+// recover from a crash for longer than the configured pause
+var ErrServerDown = errors.New("mobilizon server is down")
+
+// Resilience sets how the client rides out the crashes of a Mobilizòn
+// server, which now and then restarts after an ActivityPub failure
+type Resilience struct {
+	// a failed request is sent again up to RetryMax times, waiting between
+	// RetryWaitMin and RetryWaitMax, or as long as the server asks
+	RetryMax     int           `yaml:"retry_max"`
+	RetryWaitMin time.Duration `yaml:"retry_wait_min"`
+	RetryWaitMax time.Duration `yaml:"retry_wait_max"`
+	// after BreakerThreshold requests in a row found the server down, the
+	// breaker opens and every request waits until a health probe finds it
+	// answering again. The first probe is sent after BreakerCooldown, then
+	// one every ProbeInterval.
+	BreakerThreshold int           `yaml:"breaker_threshold"`
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown"`
+	ProbeInterval    time.Duration `yaml:"probe_interval"`
+	ProbeTimeout     time.Duration `yaml:"probe_timeout"`
+	// MaxPause is how long requests wait for the server before they fail
+	// with ErrServerDown
+	MaxPause time.Duration `yaml:"max_pause"`
+
+	// Logger hears about retries and about the breaker opening and closing
+	Logger hclog.Logger `yaml:"-"`
+}
+
+// DefaultResilience returns the timings which suit the crashes we have seen
+func DefaultResilience() Resilience {
+	return Resilience{
+		RetryMax:         4,
+		RetryWaitMin:     2 * time.Second,
+		RetryWaitMax:     30 * time.Second,
+		BreakerThreshold: 3,
+		BreakerCooldown:  SERVER_CRASH_WAIT_TIME,
+		ProbeInterval:    15 * time.Second,
+		ProbeTimeout:     10 * time.Second,
+		MaxPause:         30 * time.Minute,
+	}
+}
+
+// SetResilience changes how the client rides out server crashes
+func (c *Client) SetResilience(r Resilience) {
+	c.resilience = r
+	c.initTransport()
+}
+
+// initTransport builds the transport every request to the instance goes
+// through, retrying and pausing as the resilience settings say, and the
+// GraphQL clients on top of it
+func (c *Client) initTransport() {
+	c.transport = newResilientTransport(c.baseURL, c.resilience)
+	c.public = newAPIClient(c.baseURL+"/api", &http.Client{Transport: c.transport})
+	// without an app registration the client never authorizes
+	if c.oauth2Config.ClientID == "" {
+		c.gqlClient = c.public
+		return
+	}
+	c.gqlClient = newAPIClient(c.baseURL+"/api", c.HTTPClient(context.Background()))
+}
+
+// newResilientTransport retries failed requests on top of a circuit
+// breaker, which holds them back while the server is down
+func newResilientTransport(baseURL string, r Resilience) http.RoundTripper {
+	b := &breaker{
+		Resilience: r,
+		probe: func(ctx context.Context) error {
+			return probe(ctx, baseURL)
+		},
+	}
+	retry := retryablehttp.NewClient()
+	retry.HTTPClient = &http.Client{Transport: &breakerTransport{breaker: b, base: http.DefaultTransport}}
+	retry.RetryMax = r.RetryMax
+	retry.RetryWaitMin = r.RetryWaitMin
+	retry.RetryWaitMax = r.RetryWaitMax
+	retry.CheckRetry = RetryPolicy
+	retry.Backoff = retryablehttp.DefaultBackoff
+	// the last response is handed back as it is, for its error to be read
+	retry.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	retry.Logger = nil
+	if r.Logger != nil {
+		retry.Logger = r.Logger
+	}
+	return &retryablehttp.RoundTripper{Client: retry}
+}
+
+// probe checks whether the instance answers again. The nodeinfo document
+// is served by Mobilizòn itself, so a proxy in front of a crashed server
+// can't answer for it.
+func probe(ctx context.Context, baseURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/.well-known/nodeinfo", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("health probe: %s", resp.Status)
+	}
+	return nil
+}
+
+// serverDown reports whether a status means the server itself is down,
+// rather than that it turned the request down
+func serverDown(status int) bool {
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
+
+// breaker is a circuit breaker shared by every request of a client. Once
+// enough requests in a row found the server down it opens, and requests
+// wait until a health probe finds the server back.
+type breaker struct {
+	Resilience
+	probe func(ctx context.Context) error
+
+	mu       sync.Mutex
+	failures int
+	// outage is set while the breaker is open
+	outage *outage
+}
+
+// outage is a time the server is down. done is closed once it is back, or
+// once the breaker gave up waiting for it, in which case err says so.
+type outage struct {
+	done chan struct{}
+	err  error
+}
+
+// wait holds a request back while the breaker is open
+func (b *breaker) wait(ctx context.Context) error {
+	b.mu.Lock()
+	o := b.outage
+	b.mu.Unlock()
+	if o == nil {
+		return nil
+	}
+	select {
+	case <-o.done:
+		return o.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// record counts the requests which found the server down, and opens the
+// breaker when there are enough of them in a row
+func (b *breaker) record(down bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !down {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures < b.BreakerThreshold || b.outage != nil {
+		return
+	}
+	b.outage = &outage{done: make(chan struct{})}
+	if b.Logger != nil {
+		b.Logger.Warn("Mobilizòn is down, pausing until it recovers", "failures", b.failures)
+	}
+	go b.recover(b.outage)
+}
+
+// recover probes the server until it answers, then lets the waiting
+// requests through. After MaxPause they fail with ErrServerDown instead,
+// and the breaker closes so that later requests try again.
+func (b *breaker) recover(o *outage) {
+	start := time.Now()
+	time.Sleep(b.BreakerCooldown)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), b.ProbeTimeout)
+		err := b.probe(ctx)
+		cancel()
+		if err == nil || time.Since(start) >= b.MaxPause {
+			if err != nil {
+				o.err = fmt.Errorf("%w: no answer for %s: %v", ErrServerDown, time.Since(start).Round(time.Second), err)
+			}
+			b.mu.Lock()
+			b.failures = 0
+			b.outage = nil
+			b.mu.Unlock()
+			close(o.done)
+			if b.Logger != nil {
+				if err != nil {
+					b.Logger.Error("Mobilizòn did not recover, giving up", "after", time.Since(start).Round(time.Second), "error", err)
+				} else {
+					b.Logger.Info("Mobilizòn is back, resuming", "after", time.Since(start).Round(time.Second))
+				}
+			}
+			return
+		}
+		time.Sleep(b.ProbeInterval)
+	}
+}
+
+// breakerTransport sends requests unless the breaker is open, and tells it
+// whether they found the server down
+type breakerTransport struct {
+	breaker *breaker
+	base    http.RoundTripper
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.breaker.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	// giving up on a request says nothing about the server
+	if req.Context().Err() == nil {
+		t.breaker.record(err != nil || serverDown(resp.StatusCode))
+	}
+	return resp, err
+}
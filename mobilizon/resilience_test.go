@@ -0,0 +1,212 @@
+package mobilizon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Khan/genqlient/graphql"
+)
+
+// fastResilience keeps the tests from waiting
+func fastResilience() Resilience {
+	return Resilience{
+		RetryMax:         10,
+		RetryWaitMin:     time.Millisecond,
+		RetryWaitMax:     time.Millisecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  5 * time.Millisecond,
+		ProbeInterval:    5 * time.Millisecond,
+		ProbeTimeout:     time.Second,
+		MaxPause:         time.Second,
+	}
+}
+
+// crashingServer answers 502 for as long as down is set, as the proxy in
+// front of a crashed Mobilizòn does, and counts the requests and probes
+func crashingServer(t *testing.T, down *atomic.Bool, calls *atomic.Int32, probes *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/.well-known/nodeinfo" {
+			probes.Add(1)
+		} else {
+			calls.Add(1)
+		}
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, transport http.RoundTripper, url string) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestResilience_RetriesUntilServerAnswers(t *testing.T) {
+	var down atomic.Bool
+	var calls, probes atomic.Int32
+	down.Store(true)
+	server := crashingServer(t, &down, &calls, &probes)
+
+	// the server is back by the third try
+	server.Config.Handler = func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Load() >= 2 {
+				down.Store(false)
+			}
+			h.ServeHTTP(w, r)
+		})
+	}(server.Config.Handler)
+
+	r := fastResilience()
+	r.BreakerThreshold = 100
+	transport := newResilientTransport(server.URL, r)
+
+	resp, err := get(t, transport, server.URL+"/api")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected the request to be tried 3 times, got %d calls", calls.Load())
+	}
+	if probes.Load() != 0 {
+		t.Errorf("expected no probes below the threshold, got %d", probes.Load())
+	}
+}
+
+func TestResilience_BreakerPausesUntilProbeSucceeds(t *testing.T) {
+	var down atomic.Bool
+	var calls, probes atomic.Int32
+	down.Store(true)
+	server := crashingServer(t, &down, &calls, &probes)
+	// the server comes back while the breaker is open
+	server.Config.Handler = func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/.well-known/nodeinfo" && probes.Load() >= 2 {
+				down.Store(false)
+			}
+			h.ServeHTTP(w, r)
+		})
+	}(server.Config.Handler)
+
+	transport := newResilientTransport(server.URL, fastResilience())
+	resp, err := get(t, transport, server.URL+"/api")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+	// the threshold, then the request let through once the probe succeeded
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 calls, the breaker holding back the rest, got %d", calls.Load())
+	}
+	if probes.Load() != 3 {
+		t.Errorf("expected 3 probes, got %d", probes.Load())
+	}
+}
+
+func TestResilience_GivesUpAfterMaxPause(t *testing.T) {
+	var down atomic.Bool
+	var calls, probes atomic.Int32
+	down.Store(true)
+	server := crashingServer(t, &down, &calls, &probes)
+
+	r := fastResilience()
+	r.MaxPause = 30 * time.Millisecond
+	transport := newResilientTransport(server.URL, r)
+
+	start := time.Now()
+	_, err := get(t, transport, server.URL+"/api")
+	if !errors.Is(err, ErrServerDown) {
+		t.Fatalf("expected ErrServerDown, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < r.MaxPause {
+		t.Errorf("expected to wait at least %s, gave up after %s", r.MaxPause, elapsed)
+	}
+	if calls.Load() != int32(r.BreakerThreshold) {
+		t.Errorf("expected %d calls before the breaker opened, got %d", r.BreakerThreshold, calls.Load())
+	}
+
+	// the breaker closed again, so the next request reaches the server
+	down.Store(false)
+	resp, err := get(t, transport, server.URL+"/api")
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the next request to get through, got %v", err)
+	}
+}
+
+func TestResilience_MutationNotSentAgain(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	r := fastResilience()
+	r.RetryMax = 2
+	api := newAPIClient(server.URL+"/api", &http.Client{Transport: newResilientTransport(server.URL, r)})
+	send := func(query string) int32 {
+		calls.Store(0)
+		req := &graphql.Request{OpName: "CreateEvent", Query: query}
+		if err := api.MakeRequest(context.Background(), req, &graphql.Response{}); err == nil {
+			t.Fatal("expected an error")
+		}
+		return calls.Load()
+	}
+
+	// the server may have stored the event before failing
+	if n := send("mutation CreateEvent { createEvent { uuid } }"); n != 1 {
+		t.Errorf("expected the mutation to be sent once, got %d calls", n)
+	}
+	if n := send("query FetchEvent { event { uuid } }"); n != 3 {
+		t.Errorf("expected the query to be tried 3 times, got %d calls", n)
+	}
+}
+
+func TestResilience_MutationNotSentAgainAfterDrop(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		// the mutation arrived, but the connection breaks before the answer
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		conn.Close()
+	}))
+	t.Cleanup(server.Close)
+
+	r := fastResilience()
+	r.RetryMax = 2
+	api := newAPIClient(server.URL+"/api", &http.Client{Transport: newResilientTransport(server.URL, r)})
+	req := &graphql.Request{OpName: "CreateEvent", Query: "mutation CreateEvent { createEvent { uuid } }"}
+	if err := api.MakeRequest(context.Background(), req, &graphql.Response{}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected the mutation to be sent once, got %d calls", n)
+	}
+}
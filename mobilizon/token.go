@@ -0,0 +1,196 @@
+package mobilizon
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// how long before its expiry an access token is refreshed
+const TOKEN_REFRESH_MARGIN = time.Minute
+
+// tokenSource is the client's oauth2.TokenSource. It hands out the access
+// token, refreshing it with the refreshAuthTokens mutation shortly before
+// it expires.
+type tokenSource struct {
+	ctx context.Context
+	c   *Client
+}
+
+func (s tokenSource) Token() (*oauth2.Token, error) {
+	return s.c.validToken(s.ctx)
+}
+
+// TokenSource returns a token source handing out the client's access
+// token, which it refreshes as needed
+func (c *Client) TokenSource(ctx context.Context) oauth2.TokenSource {
+	return tokenSource{ctx: ctx, c: c}
+}
+
+// validToken returns the access token, refreshed first if it is about to
+// expire. A token whose expiry is unknown is used until the server turns it
+// down.
+func (c *Client) validToken(ctx context.Context) (*oauth2.Token, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == nil {
+		return nil, errors.New("not authorized")
+	}
+	if c.token.Expiry.IsZero() || time.Until(c.token.Expiry) > TOKEN_REFRESH_MARGIN {
+		return c.token, nil
+	}
+	if err := c.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return c.token, nil
+}
+
+// refreshStale refreshes the access token after the server turned it down,
+// unless another request has refreshed it since
+func (c *Client) refreshStale(ctx context.Context, stale *oauth2.Token) (*oauth2.Token, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != nil && c.token.AccessToken != stale.AccessToken {
+		return c.token, nil
+	}
+	if err := c.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return c.token, nil
+}
+
+// RefreshToken rotates the tokens now, and from then on saves them to
+// tokenPath whenever they are rotated
+func (c *Client) RefreshToken(ctx context.Context, tokenPath string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokenPath = tokenPath
+	return c.refresh(ctx)
+}
+
+// refresh rotates the tokens with the refreshAuthTokens mutation, sent
+// without authentication, and saves them. c.mu must be held.
+func (c *Client) refresh(ctx context.Context) error {
+	if c.token == nil || c.token.RefreshToken == "" {
+		return errors.New("no refresh token, the bot needs to be authorized again")
+	}
+	resp, err := RefreshAuthTokens(ctx, c.public, c.token.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("refreshing the access token: %w", err)
+	}
+	if resp.RefreshToken == nil || resp.RefreshToken.AccessToken == "" {
+		return errors.New("refreshing the access token: no token returned")
+	}
+	c.token = &oauth2.Token{
+		AccessToken:  resp.RefreshToken.AccessToken,
+		RefreshToken: resp.RefreshToken.RefreshToken,
+		TokenType:    "Bearer",
+		Expiry:       tokenExpiry(resp.RefreshToken.AccessToken),
+	}
+	if c.tokenPath == "" {
+		return nil
+	}
+	// the old refresh token is spent, so losing the new one means
+	// authorizing again
+	if err := c.saveToken(c.tokenPath); err != nil {
+		return fmt.Errorf("saving the refreshed token: %w", err)
+	}
+	return nil
+}
+
+// saveToken writes the token to a temporary file which then replaces the
+// auth file, so that a crash never leaves half a token behind. c.mu must
+// be held.
+func (c *Client) saveToken(path string) error {
+	if c.token == nil {
+		return fmt.Errorf("no token to save")
+	}
+	data, err := json.MarshalIndent(savedToken{c.token, strings.Join(c.scopes, " ")}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// tokenExpiry reads when an access token expires from its exp claim, as
+// Mobilizòn's access tokens are JWTs and the refresh mutation doesn't say.
+// It is zero when the token can't be read.
+func tokenExpiry(accessToken string) time.Time {
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
+
+// authTransport authenticates requests with the client's access token. When
+// the server turns the token down before its expiry, the token is
+// refreshed and the request sent once more.
+type authTransport struct {
+	c    *Client
+	base http.RoundTripper
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	token, err := t.c.validToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(withToken(req, token))
+	// a body which can't be read again can't be sent again either
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || (req.Body != nil && req.GetBody == nil) {
+		return resp, err
+	}
+
+	token, err = t.c.refreshStale(ctx, token)
+	if err != nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+	retry := withToken(req, token)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(retry)
+}
+
+// withToken returns a copy of the request carrying the token
+func withToken(req *http.Request, token *oauth2.Token) *http.Request {
+	r := req.Clone(req.Context())
+	token.SetAuthHeader(r)
+	return r
+}
@@ -0,0 +1,126 @@
+package mobilizon
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// jwt builds an unsigned access token expiring at exp
+func jwt(exp time.Time) string {
+	claims := base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, `{"exp":%d}`, exp.Unix()))
+	return "eyJhbGciOiJIUzUxMiJ9." + claims + ".c2ln"
+}
+
+// tokenServer answers Identities for the current access token only, with
+// a 401 as Mobilizòn sends for any other, and rotates the tokens on
+// refreshAuthTokens
+func tokenServer(t *testing.T, access string, refreshes *atomic.Int32) *Client {
+	t.Helper()
+	var mu sync.Mutex
+	current := "Bearer " + access
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			OperationName string `json:"operationName"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		mu.Lock()
+		defer mu.Unlock()
+
+		if req.OperationName == "RefreshAuthTokens" {
+			if r.Header.Get("Authorization") != "" {
+				t.Error("expected the refresh to be sent without a token")
+			}
+			n := refreshes.Add(1)
+			token := jwt(time.Now().Add(time.Hour))
+			current = "Bearer " + token
+			fmt.Fprintf(w, `{"data":{"refreshToken":{"accessToken":%q,"refreshToken":"refresh-%d"}}}`, token, n)
+			return
+		}
+		if r.Header.Get("Authorization") != current {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"data":null}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"identities":[]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(server.URL, "test-client-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.tokenPath = filepath.Join(t.TempDir(), "auth.json")
+	return c
+}
+
+func TestToken_RefreshedOn401(t *testing.T) {
+	var refreshes atomic.Int32
+	c := tokenServer(t, "valid", &refreshes)
+	// the server no longer takes this token, though it has not expired
+	c.token = &oauth2.Token{AccessToken: "revoked", RefreshToken: "refresh-0"}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			if _, err := c.Identities(context.Background()); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if n := refreshes.Load(); n != 1 {
+		t.Errorf("expected a single refresh shared by every request, got %d", n)
+	}
+	c2 := clientWithMock(nil)
+	if err := c2.LoadToken(c.tokenPath); err != nil {
+		t.Fatalf("expected the rotated token to be saved: %v", err)
+	}
+	if c2.token.RefreshToken != "refresh-1" || c2.token.Expiry.IsZero() {
+		t.Errorf("unexpected saved token %+v", c2.token)
+	}
+}
+
+func TestToken_RefreshedBeforeExpiry(t *testing.T) {
+	var refreshes atomic.Int32
+	access := jwt(time.Now().Add(30 * time.Second))
+	c := tokenServer(t, access, &refreshes)
+	c.token = &oauth2.Token{AccessToken: access, RefreshToken: "refresh-0", Expiry: tokenExpiry(access)}
+
+	if _, err := c.Identities(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refreshes.Load() != 1 {
+		t.Errorf("expected the token to be refreshed ahead of its expiry")
+	}
+	// the fresh token is good for an hour, and used as it is
+	if _, err := c.Identities(context.Background()); err != nil || refreshes.Load() != 1 {
+		t.Errorf("expected no second refresh, got %d (%v)", refreshes.Load(), err)
+	}
+	if _, err := os.Stat(c.tokenPath); err != nil {
+		t.Errorf("expected the rotated token to be saved: %v", err)
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	exp := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := tokenExpiry(jwt(exp)); !got.Equal(exp) {
+		t.Errorf("expected %v, got %v", exp, got)
+	}
+	if got := tokenExpiry("opaque-token"); !got.IsZero() {
+		t.Errorf("expected no expiry for an opaque token, got %v", got)
+	}
+}
@@ -35,36 +35,158 @@ type EventParams struct {
 	Tags                     []*string
 	Options                  EventOptionsInput
 	ImageURL                 string
+	MediaUUID                *uuid.UUID
 	Contact                  []*Contact
 	AttributedTo             uuid.UUID
 	OrganizedBy              uuid.UUID
 }
 
+// EventPatch holds the fields of an existing event which have changed. Nil
+// fields are not sent, so Mobilizòn leaves them as they are.
+type EventPatch struct {
+	UUID                     uuid.UUID
+	Title                    *string
+	Description              *string
+	BeginsOn                 *time.Time
+	EndsOn                   *time.Time
+	Category                 *EventCategory
+	Tags                     []*string
+	PhysicalAddress          *AddressInput
+	OnlineAddress            *string
+	ExternalParticipationURL *string
+	ImageURL                 *string
+}
+
+// Event is an event as it is published on Mobilizòn
 type Event struct {
-	ID            string
-	UUID          uuid.UUID
-	Title         string
-	Description   string
-	BeginsOn      time.Time
-	EndsOn        time.Time
-	OnlineAddress string
+	ID                       string
+	UUID                     uuid.UUID
+	URL                      string
+	Local                    bool
+	Title                    string
+	Description              string
+	BeginsOn                 time.Time
+	EndsOn                   time.Time
+	PublishAt                time.Time
+	Status                   EventStatus
+	Visibility               EventVisibility
+	JoinOptions              EventJoinOptions
+	ExternalParticipationURL string
+	Draft                    bool
+	Language                 string
+	Category                 EventCategory
+	OnlineAddress            string
+	PhoneAddress             string
+	Picture                  *Picture
+	PhysicalAddress          *Address
+	Organizer                *Actor
+	AttributedTo             *Actor
+	Contacts                 []Actor
+	Tags                     []string
+	Options                  Options
+	Metadata                 []Metadata
+	ParticipantStats         ParticipantStats
+}
+
+// Picture is an event's picture
+type Picture struct {
+	UUID     uuid.UUID
+	URL      string
+	Name     string
+	Width    int
+	Height   int
+	Blurhash string
+}
+
+// Address is an event's physical address
+type Address struct {
+	ID          string
+	Description string
+	Geom        string
+	Street      string
+	Locality    string
+	PostalCode  string
+	Region      string
+	Country     string
+	Type        string
+	URL         string
+	OriginID    string
+	Timezone    string
+}
+
+// Actor is a person or group organizing, or attributed with, an event
+type Actor struct {
+	ID                string
+	Type              ActorType
+	PreferredUsername string
+	Name              string
+	Domain            string
+	Summary           string
+	URL               string
+	AvatarURL         string
+}
+
+// Options are an event's display and participation options
+type Options struct {
+	MaximumAttendeeCapacity       int
+	RemainingAttendeeCapacity     int
+	ShowRemainingAttendeeCapacity bool
+	AnonymousParticipation        bool
+	HideNumberOfParticipants      bool
+	ShowStartTime                 bool
+	ShowEndTime                   bool
+	Timezone                      string
+	Offers                        []Offer
+	Attendees                     []string
+	Program                       string
+	CommentModeration             EventCommentModeration
+	ShowParticipationPrice        bool
+	HideOrganizerWhenGroupEvent   bool
+	IsOnline                      bool
+}
+
+// Offer is a price offer for an event
+type Offer struct {
+	Price         float64
+	PriceCurrency string
+	URL           string
+}
+
+// Metadata is a key-value pair attached to an event
+type Metadata struct {
+	Key   string
+	Title string
+	Value string
+	Type  EventMetadataType
+}
+
+// ParticipantStats counts an event's participants
+type ParticipantStats struct {
+	Going       int
+	NotApproved int
+	Participant int
 }
 
 type UploadMediaResponse struct {
 	Data struct {
 		UploadMedia struct {
+			ID   string    `json:"id"`
 			UUID uuid.UUID `json:"uuid"`
 		} `json:"uploadMedia"`
 	} `json:"data"`
-	Errors []struct {
-		Code      string   `json:"code"`
-		Field     string   `json:"string"`
-		Message   string   `json:"message"`
-		Path      []string `json:"path"`
-		Locations []struct {
-			Column int `json:"column"`
-			Line   int `json:"line"`
-		} `json:"locations"`
-		StatusCode int `json:"status_code"`
-	} `json:"errors"`
+	Errors []GraphQLError `json:"errors"`
+}
+
+// Identity is one of the profiles of the authenticated user, with the
+// groups it is a member of
+type Identity struct {
+	Actor
+	Memberships []Membership
+}
+
+// Membership is a profile's role in a group
+type Membership struct {
+	ID    string
+	Role  MemberRoleEnum
+	Group Actor
 }
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+)
+
+const OPT_OUT_FILE = "optout.json"
+
+// the kinds of opt-out entry
+const OPT_OUT_DOMAIN = "domain"
+const OPT_OUT_VENUE = "venue"
+const OPT_OUT_LOCATION = "location"
+const OPT_OUT_URL = "url"
+
+var optOutKinds = []string{OPT_OUT_DOMAIN, OPT_OUT_VENUE, OPT_OUT_LOCATION, OPT_OUT_URL}
+
+// OptOutEntry is a single venue, domain, location or URL pattern whose
+// events must never be mirrored
+//
+// Domain entries match the host of the event or source URL, including
+// subdomains. Venue entries match the event location and location entries
+// match the "City/Location" key. URL entries are regular expressions
+// matched against the event URL.
+type OptOutEntry struct {
+	Kind   string    `json:"kind"`
+	Value  string    `json:"value"`
+	Reason string    `json:"reason,omitempty"`
+	Date   time.Time `json:"date"`
+
+	re *regexp.Regexp
+}
+
+// OptOutRegistry is the persisted list of opt-out entries
+type OptOutRegistry struct {
+	Entries []OptOutEntry `json:"entries"`
+}
+
+// defaultOptOuts seeds the registry the first time it is loaded
+func defaultOptOuts() *OptOutRegistry {
+	return &OptOutRegistry{
+		Entries: []OptOutEntry{
+			{
+				Kind:   OPT_OUT_DOMAIN,
+				Value:  "bejazz.ch",
+				Reason: "They don't like us.",
+				Date:   time.Now(),
+			},
+		},
+	}
+}
+
+// loadOptOuts reads the opt-out registry, falling back to the defaults when
+// the file does not exist yet
+func loadOptOuts(path string) (*OptOutRegistry, error) {
+	dat, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		r := defaultOptOuts()
+		return r, r.compile()
+	}
+	if err != nil {
+		return nil, err
+	}
+	var r OptOutRegistry
+	if err := json.Unmarshal(dat, &r); err != nil {
+		return nil, fmt.Errorf("invalid opt-out file %s: %w", path, err)
+	}
+	if err := r.compile(); err != nil {
+		return nil, fmt.Errorf("invalid opt-out file %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// save writes the registry back to disk
+func (r *OptOutRegistry) save(path string) error {
+	Log.Debug("Saving opt-outs", "file", path)
+	data, err := json.MarshalIndent(r, "", " ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// compile validates every entry and prepares the URL patterns
+func (r *OptOutRegistry) compile() error {
+	for i := range r.Entries {
+		if err := r.Entries[i].compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *OptOutEntry) compile() error {
+	switch o.Kind {
+	case OPT_OUT_DOMAIN, OPT_OUT_VENUE, OPT_OUT_LOCATION:
+		return nil
+	case OPT_OUT_URL:
+		re, err := regexp.Compile(o.Value)
+		if err != nil {
+			return fmt.Errorf("opt-out %q: %w", o.Value, err)
+		}
+		o.re = re
+		return nil
+	}
+	return fmt.Errorf("unknown opt-out kind %q, expected one of %s", o.Kind, strings.Join(optOutKinds, ", "))
+}
+
+// Match returns the first entry matching the event, or nil
+func (r *OptOutRegistry) Match(e concertcloud.Event) *OptOutEntry {
+	for i := range r.Entries {
+		if r.Entries[i].matches(e) {
+			return &r.Entries[i]
+		}
+	}
+	return nil
+}
+
+// Add compiles and appends a new entry, refusing duplicates
+func (r *OptOutRegistry) Add(o OptOutEntry) error {
+	if err := o.compile(); err != nil {
+		return err
+	}
+	for _, existing := range r.Entries {
+		if existing.same(o.Kind, o.Value) {
+			return fmt.Errorf("%s %q is already opted out", o.Kind, o.Value)
+		}
+	}
+	r.Entries = append(r.Entries, o)
+	return nil
+}
+
+// Remove deletes the entry with the given kind and value and reports
+// whether one was found
+func (r *OptOutRegistry) Remove(kind string, value string) bool {
+	for i, o := range r.Entries {
+		if o.same(kind, value) {
+			r.Entries = append(r.Entries[:i], r.Entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// same reports whether the entry has the given kind and value. URL patterns
+// match case-sensitively, so only they are compared exactly.
+func (o *OptOutEntry) same(kind string, value string) bool {
+	if o.Kind != kind {
+		return false
+	}
+	if kind == OPT_OUT_URL {
+		return o.Value == value
+	}
+	return strings.EqualFold(o.Value, value)
+}
+
+func (o *OptOutEntry) matches(e concertcloud.Event) bool {
+	switch o.Kind {
+	case OPT_OUT_DOMAIN:
+		return hostMatches(e.URL, o.Value) || hostMatches(e.SourceURL, o.Value)
+	case OPT_OUT_VENUE:
+		return strings.EqualFold(strings.TrimSpace(e.Location), o.Value)
+	case OPT_OUT_LOCATION:
+		return strings.EqualFold(addrKey(e), o.Value)
+	case OPT_OUT_URL:
+		return o.re != nil && o.re.MatchString(e.URL)
+	}
+	return false
+}
+
+// hostMatches reports whether the URL's host is the domain or one of its
+// subdomains. Sources sometimes leave out the scheme.
+func hostMatches(rawURL string, domain string) bool {
+	if rawURL != "" && !strings.Contains(rawURL, "://") {
+		rawURL = "//" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	domain = strings.ToLower(domain)
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+)
+
+func TestOptOutEntry_Matches(t *testing.T) {
+	domain := OptOutEntry{Kind: OPT_OUT_DOMAIN, Value: "bejazz.ch"}
+	venue := OptOutEntry{Kind: OPT_OUT_VENUE, Value: "Dampfzentrale"}
+	location := OptOutEntry{Kind: OPT_OUT_LOCATION, Value: "Bern/Dampfzentrale"}
+	pattern := OptOutEntry{Kind: OPT_OUT_URL, Value: `^https://example\.com/private/`}
+
+	tests := []struct {
+		name  string
+		entry OptOutEntry
+		event concertcloud.Event
+		want  bool
+	}{
+		{"domain", domain, concertcloud.Event{URL: "https://bejazz.ch/events/1"}, true},
+		{"domain in capitals", domain, concertcloud.Event{URL: "https://BeJazz.CH/events/1"}, true},
+		{"subdomain", domain, concertcloud.Event{URL: "https://www.bejazz.ch/events/1"}, true},
+		{"source url", domain, concertcloud.Event{URL: "https://tickets.example.com/1", SourceURL: "https://www.bejazz.ch/programm"}, true},
+		{"without a scheme", domain, concertcloud.Event{URL: "www.bejazz.ch/events/1"}, true},
+		{"bare host", domain, concertcloud.Event{SourceURL: "bejazz.ch"}, true},
+		{"host merely ending with the name", domain, concertcloud.Event{URL: "https://notbejazz.ch/events/1"}, false},
+		{"name in the path", domain, concertcloud.Event{URL: "https://example.com/bejazz.ch"}, false},
+		{"no url", domain, concertcloud.Event{}, false},
+		{"venue", venue, concertcloud.Event{Location: " dampfzentrale ", City: "Bern"}, true},
+		{"other venue", venue, concertcloud.Event{Location: "Dampfzentrale Bar", City: "Bern"}, false},
+		{"venue is not the title", venue, concertcloud.Event{Title: "Dampfzentrale", Location: "Reitschule"}, false},
+		{"location", location, concertcloud.Event{Location: "Dampfzentrale", City: "bern"}, true},
+		{"location in another city", location, concertcloud.Event{Location: "Dampfzentrale", City: "Thun"}, false},
+		{"url pattern", pattern, concertcloud.Event{URL: "https://example.com/private/1"}, true},
+		{"url pattern elsewhere", pattern, concertcloud.Event{URL: "https://example.com/public/1"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.entry.compile(); err != nil {
+				t.Fatal(err)
+			}
+			if got := tt.entry.matches(tt.event); got != tt.want {
+				t.Errorf("matches(%+v) = %v, want %v", tt.event, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadOptOuts_DefaultsSkipBejazz(t *testing.T) {
+	// without an opt-out file bejazz.ch is still skipped, as it always was
+	r, err := loadOptOuts(filepath.Join(t.TempDir(), OPT_OUT_FILE))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range []string{"https://bejazz.ch/event", "https://www.bejazz.ch/event"} {
+		if r.Match(concertcloud.Event{URL: u}) == nil {
+			t.Errorf("expected %s to be opted out", u)
+		}
+	}
+	if o := r.Match(concertcloud.Event{URL: "https://dampfzentrale.ch/event"}); o != nil {
+		t.Errorf("expected no opt-out, got %+v", o)
+	}
+}
+
+func TestOptOutRegistry_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), OPT_OUT_FILE)
+	r := &OptOutRegistry{}
+	if err := r.Add(OptOutEntry{Kind: OPT_OUT_URL, Value: `/private/`}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(OptOutEntry{Kind: OPT_OUT_URL, Value: `/private/`}); err == nil {
+		t.Error("expected a duplicate to be refused")
+	}
+	// patterns match case-sensitively, so this one is another pattern
+	if err := r.Add(OptOutEntry{Kind: OPT_OUT_URL, Value: `/PRIVATE/`}); err != nil {
+		t.Errorf("expected a pattern differing in case to be added, got %v", err)
+	}
+	if err := r.Add(OptOutEntry{Kind: OPT_OUT_VENUE, Value: "Dampfzentrale"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(OptOutEntry{Kind: OPT_OUT_VENUE, Value: "dampfzentrale"}); err == nil {
+		t.Error("expected a venue differing in case to be a duplicate")
+	}
+	if err := r.Add(OptOutEntry{Kind: OPT_OUT_URL, Value: `(`}); err == nil {
+		t.Error("expected an invalid pattern to be refused")
+	}
+	if err := r.save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := loadOptOuts(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Match(concertcloud.Event{URL: "https://example.com/private/1"}) == nil {
+		t.Error("expected the loaded pattern to be compiled")
+	}
+	if loaded.Remove(OPT_OUT_URL, "/Private/") {
+		t.Error("expected no pattern to be removed for another case")
+	}
+	if !loaded.Remove(OPT_OUT_URL, "/private/") || !loaded.Remove(OPT_OUT_VENUE, "DAMPFZENTRALE") {
+		t.Errorf("expected the entries to be removed, got %+v", loaded.Entries)
+	}
+	if len(loaded.Entries) != 1 || loaded.Entries[0].Value != "/PRIVATE/" {
+		t.Errorf("expected only /PRIVATE/ to be left, got %+v", loaded.Entries)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+)
+
+// reconcileEvents looks for future events which this job mirrored earlier
+// but which are no longer in its source. They are marked missing first, and
+// only once they have stayed missing for the job's grace period are they
+// cancelled or deleted, so a single flaky scrape does no harm. Events cached
+// before jobs were recorded belong to no job until one sees them again, as
+// nothing tells which source they came from.
+//
+// It returns the number of events currently missing and the number retired.
+func reconcileEvents(ctx context.Context, job JobConfig, events []concertcloud.Event) (int, int) {
+	// a dry run, an interrupted run or an empty scrape tells us nothing
+	if *opts.NoOp || ctx.Err() != nil || len(events) == 0 {
+		return 0, 0
+	}
+	// a single full page from ConcertCloud is probably truncated
+	if job.Source == SOURCE_CONCERTCLOUD && job.Page > 0 && job.Limit > 0 && len(events) >= job.Limit {
+		Log.Info("Not reconciling a possibly truncated response", "job", job.Name, "limit", job.Limit)
+		return 0, 0
+	}
+
+	seen := make(map[string]bool, len(events))
+	for _, e := range events {
+		seen[eventKey(e)] = true
+	}
+
+	now := time.Now()
+	missing, retired := 0, 0
+	for k, ev := range existing {
+		if ev.Job != job.Name || ev.Scope != job.scope() || seen[k] || !ev.Event.Date.After(now) {
+			continue
+		}
+		// another job has picked the event up in this run
+		if _, ok := created[k]; ok {
+			continue
+		}
+
+		if ev.MissingSince == nil {
+			Log.Info("Event missing from source", "job", job.Name, "eventKey", k)
+			ev.MissingSince = &now
+			existing[k] = ev
+		}
+		missing++
+
+		if job.Vanished == MIRRORED_KEEP || now.Sub(*ev.MissingSince) < job.VanishedGrace {
+			continue
+		}
+
+		if err := retireEvent(ctx, ev.UUID, job.Vanished); err != nil {
+			Log.Error("Error retiring event", "action", job.Vanished, "eventKey", k, "error", err)
+			continue
+		}
+		Log.Info("Retired", "action", job.Vanished, "eventKey", k)
+		delete(existing, k)
+		missing--
+		retired++
+	}
+	return missing, retired
+}
+
+// keepUnseenEvents carries future events which no job saw in this run over
+// into the saved cache, so that they can be reconciled by later runs
+func keepUnseenEvents() {
+	now := time.Now()
+	for k, ev := range existing {
+		if _, ok := created[k]; !ok && ev.Event.Date.After(now) {
+			if ev.Job == "" {
+				Log.Info("Event belongs to no job and is not reconciled", "eventKey", k, "uuid", ev.UUID)
+			}
+			created[k] = ev
+		}
+	}
+}
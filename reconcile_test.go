@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+)
+
+// fakeRetirer records the events it is asked to take down
+type fakeRetirer struct {
+	calls []string
+}
+
+func (r *fakeRetirer) DeleteEvent(ctx context.Context, id uuid.UUID) error {
+	r.calls = append(r.calls, "DeleteEvent")
+	return nil
+}
+
+func (r *fakeRetirer) CancelEvent(ctx context.Context, id uuid.UUID) error {
+	r.calls = append(r.calls, "CancelEvent")
+	return nil
+}
+
+// useRetirer takes the events retired in the test down with a fakeRetirer
+func useRetirer(t *testing.T) *fakeRetirer {
+	t.Helper()
+	r := &fakeRetirer{}
+	saved := retirer
+	retirer = r
+	t.Cleanup(func() { retirer = saved })
+	return r
+}
+
+func TestReconcileEvents(t *testing.T) {
+	useNoOp(t, false)
+	now := time.Now()
+	tomorrow := now.Add(24 * time.Hour).Truncate(time.Second)
+
+	job := defaultJob("zurich")
+	job.Source, job.File = SOURCE_FILE, "zurich.json"
+	job.Vanished, job.VanishedGrace = MIRRORED_DELETE, time.Hour
+
+	event := func(location string, date time.Time) concertcloud.Event {
+		return concertcloud.Event{City: "Zürich", Location: location, Date: date}
+	}
+	seen := event("Rote Fabrik", tomorrow)
+	gone := event("Moods", tomorrow)
+	cached := func(e concertcloud.Event, missing time.Duration) ExistingEvent {
+		ev := ExistingEvent{UUID: uuid.New(), Event: e, Job: job.Name, Scope: job.scope()}
+		if missing > 0 {
+			since := now.Add(-missing)
+			ev.MissingSince = &since
+		}
+		return ev
+	}
+
+	tests := []struct {
+		name        string
+		job         func(JobConfig) JobConfig
+		missing     time.Duration
+		wantMissing int
+		wantRetired int
+		wantCalls   []string
+	}{
+		{name: "first miss is only marked", wantMissing: 1},
+		{name: "still within the grace period", missing: 30 * time.Minute, wantMissing: 1},
+		{name: "deleted after the grace period", missing: 2 * time.Hour, wantRetired: 1, wantCalls: []string{"DeleteEvent"}},
+		{
+			name:        "cancelled after the grace period",
+			job:         func(j JobConfig) JobConfig { j.Vanished = MIRRORED_CANCEL; return j },
+			missing:     2 * time.Hour,
+			wantRetired: 1,
+			wantCalls:   []string{"CancelEvent"},
+		},
+		{
+			name:        "kept never retires",
+			job:         func(j JobConfig) JobConfig { j.Vanished = MIRRORED_KEEP; return j },
+			missing:     30 * 24 * time.Hour,
+			wantMissing: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := useRetirer(t)
+			j := job
+			if tt.job != nil {
+				j = tt.job(j)
+			}
+			useCache(t, map[string]ExistingEvent{
+				eventKey(seen): cached(seen, 0),
+				eventKey(gone): cached(gone, tt.missing),
+			})
+
+			missing, retired := reconcileEvents(context.Background(), j, []concertcloud.Event{seen})
+			if missing != tt.wantMissing || retired != tt.wantRetired {
+				t.Errorf("expected %d missing and %d retired, got %d and %d", tt.wantMissing, tt.wantRetired, missing, retired)
+			}
+			if !slices.Equal(r.calls, tt.wantCalls) {
+				t.Errorf("expected calls %v, got %v", tt.wantCalls, r.calls)
+			}
+
+			ev, ok := existing[eventKey(gone)]
+			if tt.wantRetired > 0 {
+				if ok {
+					t.Error("expected the retired event to leave the cache")
+				}
+				return
+			}
+			if !ok || ev.MissingSince == nil {
+				t.Fatalf("expected the event to stay cached as missing, got %+v", ev)
+			}
+			if tt.missing > 0 && !ev.MissingSince.Equal(now.Add(-tt.missing)) {
+				t.Errorf("expected the first miss to be kept, got %s", ev.MissingSince)
+			}
+			if existing[eventKey(seen)].MissingSince != nil {
+				t.Error("expected the event still in the source not to be missing")
+			}
+		})
+	}
+}
+
+func TestReconcileEvents_Skipped(t *testing.T) {
+	now := time.Now()
+	tomorrow := now.Add(24 * time.Hour).Truncate(time.Second)
+	job := defaultJob("zurich")
+	job.Vanished, job.VanishedGrace = MIRRORED_DELETE, time.Hour
+	job.Limit = 2
+
+	gone := concertcloud.Event{City: "Zürich", Location: "Moods", Date: tomorrow}
+	events := []concertcloud.Event{
+		{City: "Zürich", Location: "Rote Fabrik", Date: tomorrow},
+		{City: "Zürich", Location: "Kaufleuten", Date: tomorrow},
+	}
+	longAgo := now.Add(-48 * time.Hour)
+	other := func(ev ExistingEvent) map[string]ExistingEvent {
+		ev.UUID, ev.Event, ev.MissingSince = uuid.New(), gone, &longAgo
+		return map[string]ExistingEvent{eventKey(gone): ev}
+	}
+
+	tests := []struct {
+		name   string
+		noop   bool
+		ctx    func() context.Context
+		job    func(JobConfig) JobConfig
+		events []concertcloud.Event
+		cache  map[string]ExistingEvent
+		found  bool
+	}{
+		{name: "noop run", noop: true, events: events, cache: other(ExistingEvent{Job: job.Name, Scope: job.scope()})},
+		{
+			name: "interrupted run",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			events: events,
+			cache:  other(ExistingEvent{Job: job.Name, Scope: job.scope()}),
+		},
+		{name: "empty scrape", cache: other(ExistingEvent{Job: job.Name, Scope: job.scope()})},
+		{
+			name:   "full single ConcertCloud page",
+			job:    func(j JobConfig) JobConfig { j.Page = 1; return j },
+			events: events,
+			cache:  other(ExistingEvent{Job: job.Name, Scope: job.scope()}),
+		},
+		{
+			name:   "another job",
+			events: events,
+			cache:  other(ExistingEvent{Job: "bern", Scope: job.scope()}),
+		},
+		{
+			name:   "another scope",
+			events: events,
+			cache:  other(ExistingEvent{Job: job.Name, Scope: SOURCE_FILE + ":other.json"}),
+		},
+		{
+			name:   "found by another job in this run",
+			events: events,
+			cache:  other(ExistingEvent{Job: job.Name, Scope: job.scope()}),
+			found:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useNoOp(t, tt.noop)
+			r := useRetirer(t)
+			useCache(t, tt.cache)
+			if tt.found {
+				created[eventKey(gone)] = tt.cache[eventKey(gone)]
+			}
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx()
+			}
+			j := job
+			if tt.job != nil {
+				j = tt.job(j)
+			}
+
+			missing, retired := reconcileEvents(ctx, j, tt.events)
+			if missing != 0 || retired != 0 {
+				t.Errorf("expected nothing reconciled, got %d missing and %d retired", missing, retired)
+			}
+			if len(r.calls) != 0 {
+				t.Errorf("expected nothing retired on Mobilizòn, got %v", r.calls)
+			}
+			if ev, ok := existing[eventKey(gone)]; !ok || !ev.MissingSince.Equal(longAgo) {
+				t.Errorf("expected the cached event untouched, got %+v", ev)
+			}
+		})
+	}
+}
+
+func TestReconcileEvents_Legacy(t *testing.T) {
+	useNoOp(t, false)
+	tomorrow := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	zurich := defaultJob("zurich")
+	zurich.City, zurich.Vanished, zurich.VanishedGrace = "Zürich", MIRRORED_CANCEL, 0
+
+	seen := concertcloud.Event{City: "Zürich", Location: "Rote Fabrik", Date: tomorrow}
+	gone := concertcloud.Event{City: "Zürich", Location: "Moods", Date: tomorrow}
+	// mirrored before the cache recorded jobs and scopes, perhaps from
+	// another source
+	legacy := map[string]ExistingEvent{eventKey(gone): {UUID: uuid.New(), Event: gone}}
+
+	useCache(t, legacy)
+	retired := useRetirer(t)
+	if missing, n := reconcileEvents(context.Background(), zurich, []concertcloud.Event{seen}); missing != 0 || n != 0 {
+		t.Errorf("expected the event to be left alone, got %d missing and %d retired", missing, n)
+	}
+	if ev := existing[eventKey(gone)]; ev.Job != "" || ev.MissingSince != nil || len(retired.calls) != 0 {
+		t.Errorf("expected the event untouched, got %+v and calls %v", ev, retired.calls)
+	}
+
+	keepUnseenEvents()
+	if _, ok := created[eventKey(gone)]; !ok {
+		t.Error("expected the event to stay cached")
+	}
+}
@@ -0,0 +1,43 @@
+package source
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+)
+
+// ConcertCloud fetches events from the ConcertCloud API. Unless the query
+// asks for a single page, every page is fetched.
+type ConcertCloud struct {
+	Fetcher concertcloud.EventFetcher
+	Params  concertcloud.QueryParams
+}
+
+// NewConcertCloud creates a source running the given query
+func NewConcertCloud(fetcher concertcloud.EventFetcher, params concertcloud.QueryParams) *ConcertCloud {
+	return &ConcertCloud{Fetcher: fetcher, Params: params}
+}
+
+func (s *ConcertCloud) Events(ctx context.Context) ([]concertcloud.Event, error) {
+	if s.Params.Page > 0 {
+		resp, err := s.Fetcher.GetEvents(ctx, s.Params)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Data, nil
+	}
+
+	var events []concertcloud.Event
+	for e, err := range s.Fetcher.AllEvents(ctx, s.Params) {
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+func (s *ConcertCloud) String() string {
+	return fmt.Sprintf("concertcloud city=%s country=%s", s.Params.City, s.Params.Country)
+}
@@ -0,0 +1,263 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+)
+
+// CSVFields are the event fields a CSV column can be mapped onto
+var CSVFields = []string{
+	"title", "date", "time", "location", "city", "country",
+	"street", "housenumber", "postcode", "locality", "state",
+	"url", "imageUrl", "type", "comment", "genres",
+}
+
+// the date layouts tried when a CSV source names none
+var csvDateFormats = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"02.01.2006 15:04",
+	"02/01/2006 15:04",
+	time.DateOnly,
+	"02.01.2006",
+	"02/01/2006",
+}
+
+// CSV reads the events of a spreadsheet export, such as the programme
+// partner organisations send us. The first row holds the column names.
+type CSV struct {
+	Path string
+	// Columns maps event fields onto column names. A field left out is
+	// read from the column named like the field, if there is one.
+	Columns map[string]string
+	// DateFormat is the Go layout of the date column and TimeFormat that of
+	// a separate time column. Without a DateFormat the usual layouts are
+	// tried.
+	DateFormat string
+	TimeFormat string
+	// Delimiter separates the columns; without one a semicolon is used if
+	// the header has more of them than commas
+	Delimiter rune
+	// Venue, City and Country fill in what a row leaves out
+	Venue   string
+	City    string
+	Country string
+	// TZ is used for dates without an offset
+	TZ *time.Location
+}
+
+// NewCSV creates a source reading a CSV file
+func NewCSV(path string) *CSV {
+	return &CSV{
+		Path:       path,
+		TimeFormat: "15:04",
+		TZ:         time.UTC,
+	}
+}
+
+func (s *CSV) Events(ctx context.Context) ([]concertcloud.Event, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	return s.decode(data)
+}
+
+func (s *CSV) String() string {
+	return "csv " + s.Path
+}
+
+// decode reads the rows, reporting every invalid one together as the
+// files of the other local sources do
+func (s *CSV) decode(data []byte) ([]concertcloud.Event, error) {
+	// spreadsheets like to start their exports with a byte order mark
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = s.delimiter(data)
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV %s: %w", s.Path, err)
+	}
+	columns, err := s.columns(header)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV %s: %w", s.Path, err)
+	}
+
+	verr := &ValidationError{Name: s.Path}
+	var events []concertcloud.Event
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV %s: %w", s.Path, err)
+		}
+		line, _ := r.FieldPos(0)
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		verr.Total++
+
+		name := fmt.Sprintf("line %d", line)
+		e, err := s.event(row, columns)
+		if err == nil {
+			err = validateEvent(e)
+		}
+		if err != nil {
+			verr.Records = append(verr.Records, RecordError{name, err})
+			continue
+		}
+		events = append(events, e)
+	}
+	if len(verr.Records) > 0 {
+		return nil, verr
+	}
+	return events, nil
+}
+
+func (s *CSV) delimiter(data []byte) rune {
+	if s.Delimiter != 0 {
+		return s.Delimiter
+	}
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+// columns finds the column of every mapped field
+func (s *CSV) columns(header []string) (map[string]int, error) {
+	index := make(map[string]int)
+	for i, h := range header {
+		index[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	columns := make(map[string]int)
+	for _, field := range CSVFields {
+		name, mapped := s.Columns[field]
+		if !mapped {
+			name = field
+		}
+		i, ok := index[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			if mapped {
+				return nil, fmt.Errorf("column %q for %s not found", name, field)
+			}
+			continue
+		}
+		columns[field] = i
+	}
+	if _, ok := columns["date"]; !ok {
+		return nil, errors.New("no date column")
+	}
+	return columns, nil
+}
+
+// event maps a row onto a ConcertCloud event
+func (s *CSV) event(row []string, columns map[string]int) (concertcloud.Event, error) {
+	get := func(field string) string {
+		if i, ok := columns[field]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	date, err := s.parseDate(get("date"), get("time"))
+	if err != nil {
+		return concertcloud.Event{}, err
+	}
+	e := concertcloud.Event{
+		Title:    get("title"),
+		Date:     date,
+		Location: get("location"),
+		City:     get("city"),
+		Country:  get("country"),
+		URL:      get("url"),
+		ImageURL: get("imageUrl"),
+		Type:     strings.ToUpper(get("type")),
+		Comment:  get("comment"),
+	}
+	if e.Location == "" {
+		e.Location = s.Venue
+	}
+	if e.City == "" {
+		e.City = s.City
+	}
+	if e.Country == "" {
+		e.Country = s.Country
+	}
+	if g := get("genres"); g != "" {
+		for _, genre := range strings.Split(g, ",") {
+			if genre = strings.TrimSpace(genre); genre != "" {
+				e.Genres = append(e.Genres, genre)
+			}
+		}
+		e.GenresText = strings.Join(e.Genres, ", ")
+	}
+
+	e.Address.Street = get("street")
+	e.Address.HouseNumber = get("housenumber")
+	e.Address.PostCode = get("postcode")
+	e.Address.Locality = get("locality")
+	e.Address.State = get("state")
+	e.Address.Country = e.Country
+	if e.Address.Locality == "" {
+		e.Address.Locality = e.City
+	}
+	return e, nil
+}
+
+// parseDate reads the date column, joined with the time column if there is
+// one. An empty date gives the zero time.
+func (s *CSV) parseDate(date string, clock string) (time.Time, error) {
+	if date == "" {
+		// reported along with anything else the row lacks
+		return time.Time{}, nil
+	}
+	tz := s.TZ
+	if tz == nil {
+		tz = time.UTC
+	}
+
+	value := date
+	layouts := csvDateFormats
+	if s.DateFormat != "" {
+		layouts = []string{s.DateFormat}
+	}
+	if clock != "" {
+		value += " " + clock
+		timeFormat := s.TimeFormat
+		if timeFormat == "" {
+			timeFormat = "15:04"
+		}
+		withTime := make([]string, len(layouts))
+		for i, l := range layouts {
+			withTime[i] = l + " " + timeFormat
+		}
+		layouts = withTime
+	}
+
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, tz); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
@@ -0,0 +1,110 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSV(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "programme.csv", "\ufeff"+`Titel;Datum;Zeit;Ort;Strasse;PLZ;Link;Beschreibung;Art
+Lesung;01.11.2026;19:30;Kulturhaus;Hauptstrasse 1;8000;https://example.org/lesung;"Erster Absatz.
+
+Zweiter Absatz mit ""Zitat""; und Semikolon.";arts
+;;;;;;;;
+Konzert;02.11.2026;20:00;;;;;;
+`)
+	zurich, err := time.LoadLocation("Europe/Zurich")
+	if err != nil {
+		t.Skip("no timezone data")
+	}
+	s := NewCSV(path)
+	s.Columns = map[string]string{
+		"title":    "Titel",
+		"date":     "Datum",
+		"time":     "Zeit",
+		"location": "Ort",
+		"street":   "Strasse",
+		"postcode": "PLZ",
+		"url":      "Link",
+		"comment":  "Beschreibung",
+		"type":     "Art",
+	}
+	s.DateFormat = "02.01.2006"
+	s.Venue, s.City, s.Country = "Gemeindesaal", "Zürich", "Switzerland"
+	s.TZ = zurich
+
+	events, err := s.Events(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", events)
+	}
+	e := events[0]
+	if e.Title != "Lesung" || e.Location != "Kulturhaus" || e.Type != "ARTS" {
+		t.Errorf("unexpected event %+v", e)
+	}
+	if want := time.Date(2026, 11, 1, 18, 30, 0, 0, time.UTC); !e.Date.Equal(want) {
+		t.Errorf("expected %v, got %v", want, e.Date)
+	}
+	if want := "Erster Absatz.\n\nZweiter Absatz mit \"Zitat\"; und Semikolon."; e.Comment != want {
+		t.Errorf("expected the multi-line description, got %q", e.Comment)
+	}
+	if e.Address.Street != "Hauptstrasse 1" || e.Address.PostCode != "8000" || e.Address.Locality != "Zürich" {
+		t.Errorf("unexpected address %+v", e.Address)
+	}
+	// the job's venue fills in what a row leaves out
+	if events[1].Location != "Gemeindesaal" || events[1].City != "Zürich" {
+		t.Errorf("expected the default venue, got %+v", events[1])
+	}
+}
+
+func TestCSV_DefaultColumns(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "events.csv", `title,date,location,genres
+Jazz Night,2026-11-03 21:00,Moods,"jazz, soul"
+`)
+	events, err := NewCSV(path).Events(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].GenresText != "jazz, soul" || events[0].Date.Hour() != 21 {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
+
+func TestCSV_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "events.csv", `title,date,location,url,type
+Fine,2026-11-03,Moods,,
+,2026-11-04,Moods,,
+Someday,soon,Moods,,
+Odd,2026-11-05,Moods,moods.ch,gig
+`)
+	_, err := NewCSV(path).Events(context.Background())
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if verr.Total != 4 || len(verr.Records) != 3 {
+		t.Fatalf("expected 3 of 4 rows invalid, got %d of %d", len(verr.Records), verr.Total)
+	}
+	want := []string{
+		"line 3: missing title",
+		`line 4: invalid date "soon"`,
+		`line 5: invalid url "moods.ch", unknown category "GIG"`,
+	}
+	for i, w := range want {
+		if got := verr.Records[i].Error(); got != w {
+			t.Errorf("row error %d: expected %q, got %q", i, w, got)
+		}
+	}
+
+	s := NewCSV(writeFile(t, dir, "other.csv", "Name,When\nX,2026-11-03\n"))
+	s.Columns = map[string]string{"title": "Titel"}
+	if _, err := s.Events(context.Background()); err == nil || !strings.Contains(err.Error(), `column "Titel"`) {
+		t.Errorf("expected an error for a missing column, got %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+package source
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+	"github.com/markjaroski/go-mobilizon-bot/mobilizon"
+)
+
+// RecordError is a record of a local file which is not a usable event
+type RecordError struct {
+	// Record names the record: its position, line or key
+	Record string
+	Err    error
+}
+
+func (e RecordError) Error() string {
+	return e.Record + ": " + e.Err.Error()
+}
+
+func (e RecordError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError lists every invalid record of a file. A file with invalid
+// records is rejected as a whole rather than mirrored in part, as the events
+// left out would otherwise look as if they had vanished.
+type ValidationError struct {
+	Name    string
+	Total   int
+	Records []RecordError
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %d of %d records invalid, first %v", e.Name, len(e.Records), e.Total, e.Records[0])
+}
+
+// DecodeEvents parses a file of events in any of the shapes we come across:
+//   - a plain JSON array, as goskyr writes
+//   - a {"data": [...]} wrapper, as the ConcertCloud API answers
+//   - an object keyed by URL, such as a copy of the event cache
+//   - JSON Lines, one event per line
+//
+// Every record is validated, and all invalid records are reported together.
+func DecodeEvents(r io.Reader, name string) ([]concertcloud.Event, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	records, err := splitRecords(bytes.TrimSpace(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid events in %s: %w", name, err)
+	}
+
+	verr := &ValidationError{Name: name, Total: len(records)}
+	events := make([]concertcloud.Event, 0, len(records))
+	for _, rec := range records {
+		var e concertcloud.Event
+		if err := json.Unmarshal(rec.data, &e); err != nil {
+			verr.Records = append(verr.Records, RecordError{rec.name, err})
+			continue
+		}
+		if err := validateEvent(e); err != nil {
+			verr.Records = append(verr.Records, RecordError{rec.name, err})
+			continue
+		}
+		events = append(events, e)
+	}
+	if len(verr.Records) > 0 {
+		return nil, verr
+	}
+	return events, nil
+}
+
+// record is the raw JSON of one event and where it was found
+type record struct {
+	name string
+	data json.RawMessage
+}
+
+// splitRecords detects the shape of a file and splits it into records
+func splitRecords(data []byte) ([]record, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	switch data[0] {
+	case '[':
+		var list []json.RawMessage
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+		return numbered("record", list), nil
+
+	case '{':
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err != nil {
+			// several objects, one per line
+			return jsonLines(data)
+		}
+		if raw, ok := obj["data"]; ok {
+			var list []json.RawMessage
+			if err := json.Unmarshal(raw, &list); err != nil {
+				return nil, fmt.Errorf("data is not a list of events: %w", err)
+			}
+			return numbered("record", list), nil
+		}
+		// the values of a keyed map are all objects, an event has strings
+		for _, raw := range obj {
+			if len(raw) == 0 || raw[0] != '{' {
+				return []record{{"line 1", data}}, nil
+			}
+		}
+		return keyed(obj), nil
+	}
+	return nil, errors.New("expected a JSON array, an object or JSON Lines")
+}
+
+func numbered(prefix string, list []json.RawMessage) []record {
+	records := make([]record, len(list))
+	for i, raw := range list {
+		records[i] = record{fmt.Sprintf("%s %d", prefix, i+1), raw}
+	}
+	return records
+}
+
+// keyed takes the events of an object keyed by URL, whose values are the
+// events or event cache entries holding them
+func keyed(obj map[string]json.RawMessage) []record {
+	keys := slices.Sorted(maps.Keys(obj))
+
+	records := make([]record, 0, len(keys))
+	for _, k := range keys {
+		raw := obj[k]
+		var entry struct {
+			Event json.RawMessage `json:"event"`
+		}
+		if err := json.Unmarshal(raw, &entry); err == nil && len(entry.Event) > 0 && entry.Event[0] == '{' {
+			raw = entry.Event
+		}
+		records = append(records, record{k, raw})
+	}
+	return records
+}
+
+// jsonLines splits JSON Lines, skipping blank lines
+func jsonLines(data []byte) ([]record, error) {
+	var records []record
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			return nil, fmt.Errorf("line %d is not valid JSON", i+1)
+		}
+		records = append(records, record{fmt.Sprintf("line %d", i+1), line})
+	}
+	return records, nil
+}
+
+// validateEvent checks that an event can be mirrored, reporting every
+// problem it has
+func validateEvent(e concertcloud.Event) error {
+	var problems []string
+	if strings.TrimSpace(e.Title) == "" {
+		problems = append(problems, "missing title")
+	}
+	if e.Date.IsZero() {
+		problems = append(problems, "missing date")
+	}
+	if strings.TrimSpace(e.Location) == "" {
+		problems = append(problems, "missing location")
+	}
+	for _, u := range []struct {
+		field, value string
+		allowData    bool
+	}{
+		{"url", e.URL, false},
+		{"imageUrl", e.ImageURL, true},
+		{"sourceUrl", e.SourceURL, false},
+	} {
+		if !validURL(u.value, u.allowData) {
+			problems = append(problems, fmt.Sprintf("invalid %s %q", u.field, u.value))
+		}
+	}
+	if e.Type != "" && !slices.Contains(mobilizon.AllEventCategory, mobilizon.EventCategory(e.Type)) {
+		problems = append(problems, fmt.Sprintf("unknown category %q", e.Type))
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// validURL accepts an empty value or an absolute http(s) URL, and data
+// URLs where a picture can be inlined
+func validURL(value string, allowData bool) bool {
+	if value == "" || (allowData && strings.HasPrefix(value, "data:")) {
+		return true
+	}
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,90 @@
+package source
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+)
+
+// File reads a file of events, such as the JSON array goskyr writes
+type File struct {
+	Path string
+}
+
+// NewFile creates a source reading a goskyr output file
+func NewFile(path string) *File {
+	return &File{Path: path}
+}
+
+func (s *File) Events(ctx context.Context) ([]concertcloud.Event, error) {
+	f, err := os.Open(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return DecodeEvents(f, s.Path)
+}
+
+func (s *File) String() string {
+	return "file " + s.Path
+}
+
+// Dir reads every goskyr output file in a directory, such as one written
+// per scraper
+type Dir struct {
+	Path string
+}
+
+// NewDir creates a source reading the *.json files in a directory
+func NewDir(path string) *Dir {
+	return &Dir{Path: path}
+}
+
+func (s *Dir) Events(ctx context.Context) ([]concertcloud.Event, error) {
+	if _, err := os.Stat(s.Path); err != nil {
+		return nil, err
+	}
+	// Glob returns the files sorted, so the order is stable between runs
+	files, err := filepath.Glob(filepath.Join(s.Path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var events []concertcloud.Event
+	for _, f := range files {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		e, err := NewFile(f).Events(ctx)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e...)
+	}
+	return events, nil
+}
+
+func (s *Dir) String() string {
+	return "dir " + s.Path
+}
+
+// Stdin reads goskyr output piped into the bot
+type Stdin struct {
+	In io.Reader
+}
+
+// NewStdin creates a source reading standard input
+func NewStdin() *Stdin {
+	return &Stdin{In: os.Stdin}
+}
+
+func (s *Stdin) Events(ctx context.Context) ([]concertcloud.Event, error) {
+	return DecodeEvents(s.In, "stdin")
+}
+
+func (s *Stdin) String() string {
+	return "stdin"
+}
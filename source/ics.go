@@ -0,0 +1,517 @@
+package source
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+)
+
+// DefaultHorizon is how far ahead recurring events are expanded
+const DefaultHorizon = 180 * 24 * time.Hour
+
+// DefaultHTTPTimeout bounds a request to a venue's server, so that one
+// which stalls can't hold up the run and every job after it
+const DefaultHTTPTimeout = 30 * time.Second
+
+// ICS reads the events of an iCalendar feed, such as the one many small
+// venues publish. Recurring events are expanded up to the horizon, and
+// past and cancelled events are left out.
+type ICS struct {
+	// URL of the feed, or the path of a local file
+	Location string
+	Horizon  time.Duration
+	// Venue, City and Country fill in what an event's LOCATION leaves out,
+	// as a venue's own feed often only names the room
+	Venue   string
+	City    string
+	Country string
+	// TZ is used for floating times when the feed does not name a timezone
+	TZ         *time.Location
+	HTTPClient *http.Client
+
+	now func() time.Time
+}
+
+// NewICS creates a source reading the feed at a URL or path
+func NewICS(location string) *ICS {
+	return &ICS{
+		Location:   location,
+		Horizon:    DefaultHorizon,
+		TZ:         time.UTC,
+		HTTPClient: &http.Client{Timeout: DefaultHTTPTimeout},
+		now:        time.Now,
+	}
+}
+
+func (s *ICS) Events(ctx context.Context) ([]concertcloud.Event, error) {
+	r, err := s.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	cal, err := parseCalendar(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid iCalendar feed %s: %w", s.Location, err)
+	}
+	return s.events(cal), nil
+}
+
+func (s *ICS) String() string {
+	return "ics " + s.Location
+}
+
+// open fetches the feed or opens the file
+func (s *ICS) open(ctx context.Context) (io.ReadCloser, error) {
+	loc := s.Location
+	if strings.HasPrefix(loc, "webcal://") {
+		loc = "https://" + strings.TrimPrefix(loc, "webcal://")
+	}
+	if !strings.HasPrefix(loc, "http://") && !strings.HasPrefix(loc, "https://") {
+		return os.Open(loc)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", loc, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("received response code %d for %s", resp.StatusCode, s.Location)
+	}
+	return resp.Body, nil
+}
+
+// events turns the VEVENTs into ConcertCloud events
+func (s *ICS) events(cal *calendar) []concertcloud.Event {
+	now := s.now()
+	horizon := s.Horizon
+	if horizon <= 0 {
+		horizon = DefaultHorizon
+	}
+	end := now.Add(horizon)
+
+	tz := s.TZ
+	if tz == nil {
+		tz = time.UTC
+	}
+	if name := cal.props.value("X-WR-TIMEZONE"); name != "" {
+		if l, err := time.LoadLocation(name); err == nil {
+			tz = l
+		}
+	}
+
+	// instances moved or changed on their own replace the generated ones
+	overrides := make(map[string]*component)
+	for _, ev := range cal.events {
+		if rid := ev.props.get("RECURRENCE-ID"); rid != nil {
+			if t, _, err := rid.time(tz); err == nil {
+				overrides[ev.props.value("UID")+"/"+t.UTC().Format(time.RFC3339)] = ev
+			}
+		}
+	}
+
+	var events []concertcloud.Event
+	for _, ev := range cal.events {
+		if ev.props.get("RECURRENCE-ID") != nil {
+			continue
+		}
+		start := ev.props.get("DTSTART")
+		if start == nil {
+			continue
+		}
+		dtstart, _, err := start.time(tz)
+		if err != nil {
+			continue
+		}
+
+		var starts []time.Time
+		if rule := ev.props.value("RRULE"); rule != "" {
+			rr, err := parseRRule(rule, tz)
+			if err != nil {
+				// we can't expand it, but the first date is still right
+				starts = []time.Time{dtstart}
+			} else {
+				starts = rr.expand(dtstart, end)
+			}
+		} else {
+			starts = []time.Time{dtstart}
+		}
+		for _, p := range ev.props.all("RDATE") {
+			starts = append(starts, p.times(tz)...)
+		}
+		excluded := make(map[string]bool)
+		for _, p := range ev.props.all("EXDATE") {
+			for _, t := range p.times(tz) {
+				excluded[t.UTC().Format(time.RFC3339)] = true
+			}
+		}
+
+		uid := ev.props.value("UID")
+		for _, t := range starts {
+			key := t.UTC().Format(time.RFC3339)
+			if excluded[key] {
+				continue
+			}
+			instance := ev
+			if o, ok := overrides[uid+"/"+key]; ok {
+				instance = o
+				if ot, _, err := o.props.get("DTSTART").time(tz); err == nil {
+					t = ot
+				}
+			}
+			if t.Before(now) || (len(starts) > 1 && t.After(end)) {
+				continue
+			}
+			if strings.EqualFold(instance.props.value("STATUS"), "CANCELLED") {
+				continue
+			}
+			events = append(events, s.event(instance, t))
+		}
+	}
+	return events
+}
+
+// event maps one VEVENT instance onto a ConcertCloud event
+func (s *ICS) event(ev *component, start time.Time) concertcloud.Event {
+	e := concertcloud.Event{
+		Title:     ev.props.value("SUMMARY"),
+		Comment:   ev.props.value("DESCRIPTION"),
+		Date:      start.UTC(),
+		URL:       ev.props.value("URL"),
+		ImageURL:  icsImage(ev),
+		SourceURL: s.Location,
+		Location:  s.Venue,
+		City:      s.City,
+		Country:   s.Country,
+	}
+	if c := ev.props.raw("CATEGORIES"); c != "" {
+		for _, g := range splitList(c) {
+			e.Genres = append(e.Genres, strings.TrimSpace(g))
+		}
+		e.GenresText = strings.Join(e.Genres, ", ")
+	}
+
+	locationToEvent(ev.props.value("LOCATION"), &e)
+
+	if geo := strings.Split(ev.props.value("GEO"), ";"); len(geo) == 2 {
+		lat, errLat := strconv.ParseFloat(strings.TrimSpace(geo[0]), 64)
+		lon, errLon := strconv.ParseFloat(strings.TrimSpace(geo[1]), 64)
+		if errLat == nil && errLon == nil {
+			// GeoJSON order
+			e.Address.Geolocacation.Type = "Point"
+			e.Address.Geolocacation.Coordinates = []float64{lon, lat}
+		}
+	}
+	return e
+}
+
+// locationToEvent splits a LOCATION of the usual "Venue, Street 1, 1000
+// City, Country" form into the address fields
+func locationToEvent(location string, e *concertcloud.Event) {
+	parts := strings.Split(location, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	if len(parts) == 0 || parts[0] == "" {
+		return
+	}
+	if e.Location == "" {
+		e.Location = parts[0]
+	}
+	if len(parts) > 1 {
+		e.Address.Street = parts[1]
+	}
+	if len(parts) > 2 {
+		postCode, locality, found := strings.Cut(parts[2], " ")
+		if found && strings.IndexFunc(postCode, func(r rune) bool { return r < '0' || r > '9' }) < 0 {
+			e.Address.PostCode = postCode
+			e.Address.Locality = locality
+		} else {
+			e.Address.Locality = parts[2]
+		}
+		if e.City == "" {
+			e.City = e.Address.Locality
+		}
+	}
+	if len(parts) > 3 {
+		e.Address.Country = parts[len(parts)-1]
+		if e.Country == "" {
+			e.Country = e.Address.Country
+		}
+	}
+	if e.Address.Locality == "" {
+		e.Address.Locality = e.City
+	}
+	if e.Address.Country == "" {
+		e.Address.Country = e.Country
+	}
+}
+
+// icsImage picks the event's picture: an IMAGE property, or else the
+// first ATTACH which looks like an image
+func icsImage(ev *component) string {
+	for _, name := range []string{"IMAGE", "ATTACH"} {
+		for _, p := range ev.props.all(name) {
+			if strings.EqualFold(p.params["ENCODING"], "BASE64") || strings.EqualFold(p.params["VALUE"], "BINARY") {
+				continue
+			}
+			if name == "IMAGE" || strings.HasPrefix(strings.ToLower(p.params["FMTTYPE"]), "image/") || isImageURL(p.value) {
+				return p.value
+			}
+		}
+	}
+	return ""
+}
+
+func isImageURL(u string) bool {
+	switch strings.ToLower(path.Ext(strings.Split(u, "?")[0])) {
+	case ".jpg", ".jpeg", ".png", ".webp", ".avif", ".gif":
+		return true
+	}
+	return false
+}
+
+// --- iCalendar parsing ---
+
+// property is one content line
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+type properties []*property
+
+func (ps properties) get(name string) *property {
+	for _, p := range ps {
+		if p.name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func (ps properties) all(name string) []*property {
+	var all []*property
+	for _, p := range ps {
+		if p.name == name {
+			all = append(all, p)
+		}
+	}
+	return all
+}
+
+// value returns the unescaped text of the first property of that name
+func (ps properties) value(name string) string {
+	return unescapeText(ps.raw(name))
+}
+
+// raw returns the text of the first property of that name as it is, for
+// lists which must be split before they are unescaped
+func (ps properties) raw(name string) string {
+	if p := ps.get(name); p != nil {
+		return p.value
+	}
+	return ""
+}
+
+// component is a VCALENDAR or VEVENT; nested components such as VALARM
+// are skipped so that their properties don't leak into the event
+type component struct {
+	props properties
+}
+
+type calendar struct {
+	component
+	events []*component
+}
+
+// parseCalendar reads the first VCALENDAR from r
+func parseCalendar(r io.Reader) (*calendar, error) {
+	cal := &calendar{}
+	var current *component
+	var stack []string
+
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		p, err := parseProperty(line)
+		if err != nil {
+			return nil, err
+		}
+		switch p.name {
+		case "BEGIN":
+			name := strings.ToUpper(p.value)
+			stack = append(stack, name)
+			if name == "VEVENT" && len(stack) == 2 {
+				current = &component{}
+			}
+			continue
+		case "END":
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("unexpected END:%s", p.value)
+			}
+			if stack[len(stack)-1] == "VEVENT" && len(stack) == 2 {
+				cal.events = append(cal.events, current)
+				current = nil
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		switch {
+		case len(stack) == 1:
+			cal.props = append(cal.props, p)
+		case len(stack) == 2 && current != nil:
+			current.props = append(current.props, p)
+		}
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("missing END:%s", stack[len(stack)-1])
+	}
+	if cal.props == nil && cal.events == nil {
+		return nil, fmt.Errorf("no VCALENDAR found")
+	}
+	return cal, nil
+}
+
+// unfold joins continuation lines, which start with a space or a tab
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// parseProperty splits NAME;PARAM=value;PARAM="quoted":value
+func parseProperty(line string) (*property, error) {
+	colon, quoted := -1, false
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return nil, fmt.Errorf("invalid content line %q", line)
+	}
+
+	head := strings.Split(line[:colon], ";")
+	p := &property{
+		name:   strings.ToUpper(head[0]),
+		params: make(map[string]string),
+		value:  line[colon+1:],
+	}
+	for _, param := range head[1:] {
+		k, v, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return p, nil
+}
+
+// time parses a DATE or DATE-TIME value, which may be UTC, in the TZID
+// given or floating. It also reports whether the value is a whole day.
+func (p *property) time(tz *time.Location) (time.Time, bool, error) {
+	return parseICSTime(p.value, p.params["TZID"], tz)
+}
+
+// times parses a comma separated list of dates, as EXDATE and RDATE use
+func (p *property) times(tz *time.Location) []time.Time {
+	var times []time.Time
+	for _, v := range strings.Split(p.value, ",") {
+		if t, _, err := parseICSTime(v, p.params["TZID"], tz); err == nil {
+			times = append(times, t)
+		}
+	}
+	return times
+}
+
+func parseICSTime(value string, tzid string, tz *time.Location) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	loc := tz
+	if tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	switch {
+	case len(value) == 8:
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	case strings.HasSuffix(value, "Z"):
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// unescapeText undoes the escaping of TEXT values
+func unescapeText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// splitList splits on the commas which are not escaped
+func splitList(s string) []string {
+	var parts []string
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i < len(s)-1:
+			b.WriteByte(s[i])
+			b.WriteByte(s[i+1])
+			i++
+		case s[i] == ',':
+			parts = append(parts, unescapeText(b.String()))
+			b.Reset()
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return append(parts, unescapeText(b.String()))
+}
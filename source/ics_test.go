@@ -0,0 +1,193 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// icsNow is the fixed "now" of the feed tests: a Monday
+var icsNow = time.Date(2026, 11, 2, 12, 0, 0, 0, time.UTC)
+
+const icsFeed = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Bad Bonn//Events//EN\r\n" +
+	"X-WR-TIMEZONE:Europe/Zurich\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:single@badbonn.ch\r\n" +
+	"DTSTART;TZID=Europe/Zurich:20261107T200000\r\n" +
+	"SUMMARY:Kilbi warm-up\r\n" +
+	"DESCRIPTION:Doors 19:00\\nShow 20:00\\, sharp\r\n" +
+	"LOCATION:Bad Bonn\\, Bonnstrasse 2\\, 3186 Düdingen\\, Switzerland\r\n" +
+	"GEO:46.8497;7.1872\r\n" +
+	"URL:https://badbonn.ch/events/warm-up\r\n" +
+	"ATTACH;FMTTYPE=application/pdf:https://badbonn.ch/flyer.pdf\r\n" +
+	"ATTACH:https://badbonn.ch/poster.jpg\r\n" +
+	"CATEGORIES:Rock,Noise\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"DESCRIPTION:Reminder\r\n" +
+	"TRIGGER:-PT1H\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:past@badbonn.ch\r\n" +
+	"DTSTART:20261001T180000Z\r\n" +
+	"SUMMARY:Already over\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:cancelled@badbonn.ch\r\n" +
+	"DTSTART:20261201T180000Z\r\n" +
+	"SUMMARY:Called off\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:jam@badbonn.ch\r\n" +
+	"DTSTART;TZID=Europe/Zurich:20261006T210000\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=TU\r\n" +
+	"EXDATE;TZID=Europe/Zurich:20261110T210000\r\n" +
+	"SUMMARY:Tuesday jam\r\n" +
+	"LOCATION:Bar\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:jam@badbonn.ch\r\n" +
+	"RECURRENCE-ID;TZID=Europe/Zurich:20261117T210000\r\n" +
+	"DTSTART;TZID=Europe/Zurich:20261117T220000\r\n" +
+	"SUMMARY:Tuesday jam (late)\r\n" +
+	"LOCATION:Bar\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func testICS(location string) *ICS {
+	s := NewICS(location)
+	s.Horizon = 21 * 24 * time.Hour
+	s.City = "Düdingen"
+	s.now = func() time.Time { return icsNow }
+	return s
+}
+
+func TestICS(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "events.ics", icsFeed)
+
+	events, err := testICS(path).Events(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var titles []string
+	for _, e := range events {
+		titles = append(titles, e.Title+" "+e.Date.Format(time.RFC3339))
+	}
+	want := []string{
+		"Kilbi warm-up 2026-11-07T19:00:00Z",
+		"Tuesday jam 2026-11-03T20:00:00Z",
+		// 10 November is excluded and 17 November moved
+		"Tuesday jam (late) 2026-11-17T21:00:00Z",
+		// the horizon ends on 23 November
+	}
+	if got := strings.Join(titles, "\n"); got != strings.Join(want, "\n") {
+		t.Fatalf("unexpected events:\n%s\nwant:\n%s", got, strings.Join(want, "\n"))
+	}
+
+	e := events[0]
+	if e.Comment != "Doors 19:00\nShow 20:00, sharp" {
+		t.Errorf("alarm or escaping leaked into the description: %q", e.Comment)
+	}
+	if e.Location != "Bad Bonn" || e.Address.Street != "Bonnstrasse 2" || e.Address.PostCode != "3186" ||
+		e.Address.Locality != "Düdingen" || e.Country != "Switzerland" {
+		t.Errorf("unexpected address %+v", e)
+	}
+	if c := e.Address.Geolocacation.Coordinates; len(c) != 2 || c[0] != 7.1872 || c[1] != 46.8497 {
+		t.Errorf("expected lon;lat coordinates, got %v", c)
+	}
+	if e.URL != "https://badbonn.ch/events/warm-up" || e.ImageURL != "https://badbonn.ch/poster.jpg" {
+		t.Errorf("unexpected URL %q or image %q", e.URL, e.ImageURL)
+	}
+	if e.GenresText != "Rock, Noise" {
+		t.Errorf("unexpected genres %q", e.GenresText)
+	}
+	if events[1].City != "Düdingen" || events[1].Location != "Bar" {
+		t.Errorf("expected the job's city to fill in, got %+v", events[1])
+	}
+}
+
+func TestICS_HTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/events.ics" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Write([]byte(icsFeed))
+	}))
+	defer server.Close()
+
+	events, err := testICS(server.URL + "/events.ics").Events(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 3 {
+		t.Errorf("expected 3 events, got %d", len(events))
+	}
+
+	if _, err := testICS(server.URL + "/missing.ics").Events(context.Background()); err == nil {
+		t.Error("expected an error for a missing feed")
+	}
+}
+
+func TestICS_Invalid(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "events.ics", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:Unterminated\r\n")
+	if _, err := testICS(path).Events(context.Background()); err == nil {
+		t.Error("expected an error for an unterminated calendar")
+	}
+}
+
+func TestRRule(t *testing.T) {
+	zurich, _ := time.LoadLocation("Europe/Zurich")
+	start := time.Date(2026, 1, 31, 20, 0, 0, 0, zurich) // a Saturday
+	end := time.Date(2026, 12, 31, 0, 0, 0, 0, zurich)
+
+	tests := []struct {
+		rule string
+		want []string
+	}{
+		{"FREQ=DAILY;COUNT=3", []string{"2026-01-31", "2026-02-01", "2026-02-02"}},
+		{"FREQ=DAILY;INTERVAL=10;UNTIL=20260220", []string{"2026-01-31", "2026-02-10", "2026-02-20"}},
+		{"FREQ=WEEKLY;BYDAY=FR,SA;COUNT=4", []string{"2026-01-31", "2026-02-06", "2026-02-07", "2026-02-13"}},
+		{"FREQ=WEEKLY;INTERVAL=2;COUNT=3", []string{"2026-01-31", "2026-02-14", "2026-02-28"}},
+		// months without a 31st are skipped
+		{"FREQ=MONTHLY;COUNT=3", []string{"2026-01-31", "2026-03-31", "2026-05-31"}},
+		{"FREQ=MONTHLY;BYDAY=1FR;COUNT=3", []string{"2026-02-06", "2026-03-06", "2026-04-03"}},
+		{"FREQ=MONTHLY;BYDAY=-1SA;COUNT=3", []string{"2026-01-31", "2026-02-28", "2026-03-28"}},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=2", []string{"2026-01-31", "2026-02-28"}},
+		{"FREQ=YEARLY;BYMONTH=3,6;BYDAY=2SA;COUNT=2", []string{"2026-03-14", "2026-06-13"}},
+		{"FREQ=YEARLY", []string{"2026-01-31"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			r, err := parseRRule(tt.rule, zurich)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, d := range r.expand(start, end) {
+				if d.Hour() != 20 {
+					t.Errorf("expected the start time to be kept, got %s", d)
+				}
+				got = append(got, d.Format(time.DateOnly))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, rule := range []string{"FREQ=HOURLY", "FREQ=DAILY;BYSETPOS=1", "FREQ=WEEKLY;BYDAY=XX"} {
+		if _, err := parseRRule(rule, zurich); err == nil {
+			t.Errorf("expected an error for %s", rule)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+)
+
+// errNotFound marks a page which has gone, which a JSONLD source skips
+var errNotFound = errors.New("not found")
+
+// the script elements holding JSON-LD
+var jsonLDScript = regexp.MustCompile(`(?is)<script[^>]*type\s*=\s*["']?application/ld\+json["']?[^>]*>(.*?)</script>`)
+
+// JSONLD reads the schema.org events venue websites embed as JSON-LD, from
+// a list of pages or from the pages listed in a sitemap
+type JSONLD struct {
+	Pages   []string
+	Sitemap string
+	// Match limits the sitemap to the pages whose URL it matches
+	Match *regexp.Regexp
+	// Venue, City and Country fill in what an event's location leaves out
+	Venue   string
+	City    string
+	Country string
+	// TZ is used for dates without an offset
+	TZ         *time.Location
+	HTTPClient *http.Client
+
+	now func() time.Time
+}
+
+// NewJSONLD creates a source reading the given pages
+func NewJSONLD(pages ...string) *JSONLD {
+	return &JSONLD{
+		Pages:      pages,
+		TZ:         time.UTC,
+		HTTPClient: &http.Client{Timeout: DefaultHTTPTimeout},
+		now:        time.Now,
+	}
+}
+
+func (s *JSONLD) Events(ctx context.Context) ([]concertcloud.Event, error) {
+	pages := s.Pages
+	if s.Sitemap != "" {
+		found, err := s.sitemapPages(ctx, s.Sitemap, 0)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages[:len(pages):len(pages)], found...)
+	}
+
+	now := s.now()
+	seen := make(map[string]bool)
+	var events []concertcloud.Event
+	for _, page := range pages {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		body, err := s.get(ctx, page)
+		if errors.Is(err, errNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		base, _ := url.Parse(page)
+		for _, e := range s.extract(body, base) {
+			// list pages and detail pages often carry the same event
+			key := e.Title + "/" + e.Date.Format(time.RFC3339)
+			if seen[key] || e.Date.Before(now) {
+				continue
+			}
+			seen[key] = true
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (s *JSONLD) String() string {
+	if s.Sitemap != "" {
+		return "jsonld sitemap " + s.Sitemap
+	}
+	return fmt.Sprintf("jsonld %d pages", len(s.Pages))
+}
+
+// get fetches a page
+func (s *JSONLD) get(ctx context.Context, page string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", page, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("%s: %w", page, errNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("received response code %d for %s", resp.StatusCode, page)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// sitemapPages lists the matching pages of a sitemap, following a sitemap
+// index one level down
+func (s *JSONLD) sitemapPages(ctx context.Context, sitemap string, depth int) ([]string, error) {
+	body, err := s.get(ctx, sitemap)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		XMLName  xml.Name
+		URLs     []string `xml:"url>loc"`
+		Sitemaps []string `xml:"sitemap>loc"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("invalid sitemap %s: %w", sitemap, err)
+	}
+
+	var pages []string
+	for _, u := range doc.URLs {
+		u = strings.TrimSpace(u)
+		if s.Match == nil || s.Match.MatchString(u) {
+			pages = append(pages, u)
+		}
+	}
+	if depth == 0 {
+		for _, sm := range doc.Sitemaps {
+			found, err := s.sitemapPages(ctx, strings.TrimSpace(sm), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			pages = append(pages, found...)
+		}
+	}
+	return pages, nil
+}
+
+// extract finds the schema.org events in the JSON-LD of a page. Scripts
+// which are not valid JSON are skipped, as sites get this wrong often.
+func (s *JSONLD) extract(body []byte, base *url.URL) []concertcloud.Event {
+	var events []concertcloud.Event
+	for _, m := range jsonLDScript.FindAllSubmatch(body, -1) {
+		var doc any
+		if err := json.Unmarshal(m[1], &doc); err != nil {
+			continue
+		}
+		for _, node := range findEvents(doc) {
+			if e, ok := s.event(node, base); ok {
+				events = append(events, e)
+			}
+		}
+	}
+	return events
+}
+
+// findEvents walks a JSON-LD document for Event objects, including those
+// in an @graph, an ItemList or a page's mainEntity
+func findEvents(doc any) []map[string]any {
+	var events []map[string]any
+	switch v := doc.(type) {
+	case []any:
+		for _, n := range v {
+			events = append(events, findEvents(n)...)
+		}
+	case map[string]any:
+		if isEventType(v["@type"]) {
+			return []map[string]any{v}
+		}
+		for _, n := range v {
+			events = append(events, findEvents(n)...)
+		}
+	}
+	return events
+}
+
+// isEventType accepts Event and its subtypes, such as MusicEvent
+func isEventType(t any) bool {
+	switch v := t.(type) {
+	case string:
+		v = strings.TrimPrefix(strings.TrimPrefix(v, "http://schema.org/"), "https://schema.org/")
+		return strings.HasSuffix(v, "Event") || v == "Festival"
+	case []any:
+		for _, n := range v {
+			if isEventType(n) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// event maps a schema.org event onto a ConcertCloud event
+func (s *JSONLD) event(node map[string]any, base *url.URL) (concertcloud.Event, bool) {
+	start, ok := s.parseDate(text(node["startDate"]))
+	if !ok {
+		return concertcloud.Event{}, false
+	}
+	status := text(node["eventStatus"])
+	if strings.HasSuffix(status, "EventCancelled") || strings.HasSuffix(status, "EventPostponed") {
+		return concertcloud.Event{}, false
+	}
+
+	e := concertcloud.Event{
+		Title:     html.UnescapeString(text(node["name"])),
+		Date:      start.UTC(),
+		URL:       resolve(base, text(node["url"])),
+		ImageURL:  resolve(base, imageURL(node["image"])),
+		SourceURL: base.String(),
+		Location:  s.Venue,
+		City:      s.City,
+		Country:   s.Country,
+	}
+	if e.URL == "" {
+		e.URL = base.String()
+	}
+
+	var comment []string
+	if d := html.UnescapeString(text(node["description"])); d != "" {
+		comment = append(comment, d)
+	}
+	if p := names(node["performer"]); len(p) > 0 {
+		comment = append(comment, "With: "+strings.Join(p, ", "))
+	}
+	if o := offers(node["offers"], base); o != "" {
+		comment = append(comment, "Tickets: "+o)
+	}
+	e.Comment = strings.Join(comment, "\n\n")
+
+	placeToEvent(first(node["location"]), &e)
+	return e, true
+}
+
+// placeToEvent copies a schema.org Place, or a plain location string,
+// into the address fields
+func placeToEvent(loc any, e *concertcloud.Event) {
+	switch v := loc.(type) {
+	case string:
+		locationToEvent(v, e)
+		return
+	case map[string]any:
+		if n := html.UnescapeString(text(v["name"])); n != "" && e.Location == "" {
+			e.Location = n
+		}
+		switch a := first(v["address"]).(type) {
+		case string:
+			// "Street 1, 1000 City, Country"
+			locationToEvent(e.Location+", "+a, e)
+		case map[string]any:
+			e.Address.Street = text(a["streetAddress"])
+			e.Address.PostCode = text(a["postalCode"])
+			e.Address.Locality = text(a["addressLocality"])
+			e.Address.State = text(a["addressRegion"])
+			e.Address.Country = text(a["addressCountry"])
+			if c, ok := a["addressCountry"].(map[string]any); ok {
+				e.Address.Country = text(c["name"])
+			}
+		}
+		if geo, ok := v["geo"].(map[string]any); ok {
+			lat, errLat := number(geo["latitude"])
+			lon, errLon := number(geo["longitude"])
+			if errLat == nil && errLon == nil {
+				e.Address.Geolocacation.Type = "Point"
+				e.Address.Geolocacation.Coordinates = []float64{lon, lat}
+			}
+		}
+	}
+	if e.City == "" {
+		e.City = e.Address.Locality
+	}
+	if e.Country == "" {
+		e.Country = e.Address.Country
+	}
+	if e.Address.Locality == "" {
+		e.Address.Locality = e.City
+	}
+}
+
+// parseDate reads the ISO 8601 dates schema.org uses, with or without a
+// time and an offset
+func (s *JSONLD) parseDate(v string) (time.Time, bool) {
+	tz := s.TZ
+	if tz == nil {
+		tz = time.UTC
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04", time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, v, tz); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// offers summarises the price and ticket link of one or more Offers
+func offers(v any, base *url.URL) string {
+	var parts []string
+	for _, o := range list(v) {
+		offer, ok := o.(map[string]any)
+		if !ok {
+			continue
+		}
+		price := text(offer["price"])
+		if price == "" {
+			price = text(offer["lowPrice"])
+		}
+		var s []string
+		if price != "" {
+			s = append(s, strings.TrimSpace(price+" "+text(offer["priceCurrency"])))
+		}
+		if u := resolve(base, text(offer["url"])); u != "" {
+			s = append(s, u)
+		}
+		if len(s) > 0 {
+			parts = append(parts, strings.Join(s, " "))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// imageURL takes the first image, which may be a URL or an ImageObject
+func imageURL(v any) string {
+	switch i := first(v).(type) {
+	case string:
+		return i
+	case map[string]any:
+		if u := text(i["url"]); u != "" {
+			return u
+		}
+		return text(i["contentUrl"])
+	}
+	return ""
+}
+
+// names lists the names of people or groups, given as strings or objects
+func names(v any) []string {
+	var n []string
+	for _, p := range list(v) {
+		switch x := p.(type) {
+		case string:
+			n = append(n, x)
+		case map[string]any:
+			if name := html.UnescapeString(text(x["name"])); name != "" {
+				n = append(n, name)
+			}
+		}
+	}
+	return n
+}
+
+// text reads a JSON-LD value as a string
+func text(v any) string {
+	switch x := first(v).(type) {
+	case string:
+		return strings.TrimSpace(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case map[string]any:
+		// {"@value": ...} and {"@id": ...} forms
+		if s := text(x["@value"]); s != "" {
+			return s
+		}
+		return text(x["@id"])
+	}
+	return ""
+}
+
+func number(v any) (float64, error) {
+	switch x := v.(type) {
+	case float64:
+		return x, nil
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(x), 64)
+	}
+	return 0, fmt.Errorf("not a number: %v", v)
+}
+
+// list turns a single value or an array into a slice
+func list(v any) []any {
+	if a, ok := v.([]any); ok {
+		return a
+	}
+	if v == nil {
+		return nil
+	}
+	return []any{v}
+}
+
+func first(v any) any {
+	if l := list(v); len(l) > 0 {
+		return l[0]
+	}
+	return nil
+}
+
+// resolve makes a link found on a page absolute
+func resolve(base *url.URL, ref string) string {
+	if ref == "" || base == nil {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
@@ -0,0 +1,159 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// jsonLDNow is the fixed "now" of the JSON-LD tests
+var jsonLDNow = time.Date(2026, 11, 2, 12, 0, 0, 0, time.UTC)
+
+const eventPage = `<!doctype html>
+<html><head>
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "Organization", "name": "Pôle Sud"}</script>
+<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "@type": "MusicEvent",
+  "name": "Jazz &amp; more",
+  "startDate": "2026-11-07T20:30:00+01:00",
+  "url": "/events/jazz",
+  "image": [{"@type": "ImageObject", "url": "/img/jazz.jpg"}],
+  "description": "An evening of jazz",
+  "eventStatus": "https://schema.org/EventScheduled",
+  "location": {
+    "@type": "Place",
+    "name": "Pôle Sud",
+    "address": {
+      "@type": "PostalAddress",
+      "streetAddress": "Avenue Jean-Jacques Mercier 3",
+      "postalCode": "1003",
+      "addressLocality": "Lausanne",
+      "addressCountry": {"@type": "Country", "name": "Switzerland"}
+    },
+    "geo": {"@type": "GeoCoordinates", "latitude": "46.5168", "longitude": 6.6271}
+  },
+  "performer": [{"@type": "MusicGroup", "name": "The Trio"}, "Guest"],
+  "offers": {"@type": "Offer", "price": 25, "priceCurrency": "CHF", "url": "https://tickets.example.org/jazz"}
+}
+</script>
+</head><body></body></html>`
+
+const listPage = `<html><head>
+<script type='application/ld+json'>
+{"@context": "https://schema.org", "@graph": [
+  {"@type": "WebPage", "name": "Agenda"},
+  {"@type": "ItemList", "itemListElement": [
+    {"@type": "ListItem", "item": {"@type": ["Event", "MusicEvent"], "name": "Jazz &amp; more", "startDate": "2026-11-07T20:30:00+01:00", "location": "Pôle Sud, Avenue Jean-Jacques Mercier 3, 1003 Lausanne"}},
+    {"@type": "ListItem", "item": {"@type": "TheaterEvent", "name": "Floating time", "startDate": "2026-11-20T19:00", "location": "Pôle Sud"}},
+    {"@type": "ListItem", "item": {"@type": "Event", "name": "Called off", "startDate": "2026-11-21", "eventStatus": "https://schema.org/EventCancelled"}},
+    {"@type": "ListItem", "item": {"@type": "Event", "name": "Last year", "startDate": "2025-11-21T20:00:00Z"}}
+  ]}
+]}
+</script>
+<script type="application/ld+json">{ this is not json }</script>
+</head></html>`
+
+func jsonLDServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/events/jazz":
+			w.Write([]byte(eventPage))
+		case "/agenda":
+			w.Write([]byte(listPage))
+		case "/sitemap.xml":
+			w.Write([]byte(`<?xml version="1.0"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>` + server.URL + `/sitemap-pages.xml</loc></sitemap>
+</sitemapindex>`))
+		case "/sitemap-pages.xml":
+			w.Write([]byte(`<?xml version="1.0"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>` + server.URL + `/about</loc></url>
+  <url><loc>` + server.URL + `/events/jazz</loc></url>
+  <url><loc>` + server.URL + `/events/gone</loc></url>
+</urlset>`))
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testJSONLD(pages ...string) *JSONLD {
+	s := NewJSONLD(pages...)
+	s.TZ, _ = time.LoadLocation("Europe/Zurich")
+	s.now = func() time.Time { return jsonLDNow }
+	return s
+}
+
+func TestJSONLD(t *testing.T) {
+	server := jsonLDServer(t)
+
+	events, err := testJSONLD(server.URL+"/events/jazz", server.URL+"/agenda").Events(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var titles []string
+	for _, e := range events {
+		titles = append(titles, e.Title+" "+e.Date.Format(time.RFC3339))
+	}
+	want := "Jazz & more 2026-11-07T19:30:00Z,Floating time 2026-11-20T18:00:00Z"
+	if got := strings.Join(titles, ","); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	e := events[0]
+	if e.URL != server.URL+"/events/jazz" || e.ImageURL != server.URL+"/img/jazz.jpg" {
+		t.Errorf("expected absolute links, got %q and %q", e.URL, e.ImageURL)
+	}
+	if e.Location != "Pôle Sud" || e.City != "Lausanne" || e.Country != "Switzerland" ||
+		e.Address.Street != "Avenue Jean-Jacques Mercier 3" || e.Address.PostCode != "1003" {
+		t.Errorf("unexpected address %+v", e)
+	}
+	if c := e.Address.Geolocacation.Coordinates; len(c) != 2 || c[0] != 6.6271 || c[1] != 46.5168 {
+		t.Errorf("expected lon;lat coordinates, got %v", c)
+	}
+	want = "An evening of jazz\n\nWith: The Trio, Guest\n\nTickets: 25 CHF https://tickets.example.org/jazz"
+	if e.Comment != want {
+		t.Errorf("got comment %q, want %q", e.Comment, want)
+	}
+	if events[1].Location != "Pôle Sud" || events[1].URL != server.URL+"/agenda" {
+		t.Errorf("expected the page to stand in for the event URL, got %+v", events[1])
+	}
+}
+
+func TestJSONLD_Sitemap(t *testing.T) {
+	server := jsonLDServer(t)
+
+	s := testJSONLD()
+	s.Sitemap = server.URL + "/sitemap.xml"
+	s.Match = regexp.MustCompile(`/events/`)
+	events, err := s.Events(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the page which has gone is skipped
+	if len(events) != 1 || events[0].Title != "Jazz & more" {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
+
+func TestJSONLD_BrokenPage(t *testing.T) {
+	server := jsonLDServer(t)
+
+	if _, err := testJSONLD(server.URL+"/events/jazz", server.URL+"/broken").Events(context.Background()); err == nil {
+		t.Error("expected an error for a broken page")
+	}
+}
@@ -0,0 +1,159 @@
+package source
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+	"github.com/markjaroski/go-mobilizon-bot/mobilizon"
+)
+
+// DefaultSearchPageSize is how many events are asked for per page of a
+// Mobilizòn search
+const DefaultSearchPageSize = 50
+
+// maxSearchPages stops a search whose total never adds up
+const maxSearchPages = 100
+
+// EventSearcher searches the events of a Mobilizòn instance, as
+// mobilizon.Client does
+type EventSearcher interface {
+	SearchEventPage(ctx context.Context, p mobilizon.SearchParams) ([]mobilizon.Event, int, error)
+}
+
+// Mobilizon mirrors the events a neighbouring Mobilizòn instance publishes
+// for an area. The events keep their original URL, so they link back to
+// the instance they came from.
+type Mobilizon struct {
+	// Remote searches the neighbouring instance, anonymously
+	Remote EventSearcher
+	// Instance is the URL of the neighbouring instance
+	Instance string
+	// Local searches our own instance. Events it already has, because they
+	// were federated to it, are left out.
+	Local EventSearcher
+	// Search holds the area, and any other filter; the dates are set from
+	// the horizon
+	Search   mobilizon.SearchParams
+	Horizon  time.Duration
+	PageSize int
+
+	now func() time.Time
+}
+
+// NewMobilizon creates a source searching the instance at the given URL
+// for the events around a geohash
+func NewMobilizon(instance string, location string, radius float64) *Mobilizon {
+	return &Mobilizon{
+		Remote:   mobilizon.NewPublicClient(instance),
+		Instance: instance,
+		Search:   mobilizon.SearchParams{Location: location, Radius: radius},
+		Horizon:  DefaultHorizon,
+		PageSize: DefaultSearchPageSize,
+		now:      time.Now,
+	}
+}
+
+func (s *Mobilizon) Events(ctx context.Context) ([]concertcloud.Event, error) {
+	params := s.Search
+	params.BeginsOn = s.now()
+	if s.Horizon > 0 {
+		params.EndsOn = params.BeginsOn.Add(s.Horizon)
+	}
+
+	remote, err := s.search(ctx, s.Remote, params)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool)
+	if s.Local != nil {
+		local, err := s.search(ctx, s.Local, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range local {
+			known[e.URL] = true
+		}
+	}
+
+	var events []concertcloud.Event
+	for _, e := range remote {
+		// events the neighbour has from other instances, our own among
+		// them, belong to those instances
+		if !e.Local || known[e.URL] || e.Status == mobilizon.EventStatusCancelled {
+			continue
+		}
+		events = append(events, FromMobilizon(e, s.Instance))
+	}
+	return events, nil
+}
+
+func (s *Mobilizon) String() string {
+	return "mobilizon " + s.Instance
+}
+
+// search fetches every page of a search
+func (s *Mobilizon) search(ctx context.Context, searcher EventSearcher, params mobilizon.SearchParams) ([]mobilizon.Event, error) {
+	params.Limit = s.PageSize
+	if params.Limit <= 0 {
+		params.Limit = DefaultSearchPageSize
+	}
+
+	var events []mobilizon.Event
+	for page := 1; page <= maxSearchPages; page++ {
+		params.Page = page
+		found, total, err := searcher.SearchEventPage(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, found...)
+		if len(found) == 0 || len(events) >= total {
+			break
+		}
+	}
+	return events, nil
+}
+
+// FromMobilizon maps a Mobilizòn event onto a ConcertCloud event. The
+// event keeps its Mobilizòn URL, and sourceURL names where it was found.
+func FromMobilizon(m mobilizon.Event, sourceURL string) concertcloud.Event {
+	e := concertcloud.Event{
+		Title:     m.Title,
+		Comment:   m.Description,
+		Date:      m.BeginsOn.UTC(),
+		URL:       m.URL,
+		Type:      string(m.Category),
+		Genres:    m.Tags,
+		SourceURL: sourceURL,
+	}
+	e.GenresText = strings.Join(m.Tags, ", ")
+	if m.Picture != nil {
+		e.ImageURL = m.Picture.URL
+	}
+
+	if a := m.PhysicalAddress; a != nil {
+		e.Location = a.Description
+		e.City = a.Locality
+		e.Country = a.Country
+		e.Address.Street = a.Street
+		e.Address.PostCode = a.PostalCode
+		e.Address.Locality = a.Locality
+		e.Address.State = a.Region
+		e.Address.Country = a.Country
+		// Mobilizòn writes points as "lon;lat"
+		if lon, lat, ok := strings.Cut(a.Geom, ";"); ok {
+			x, errLon := strconv.ParseFloat(lon, 64)
+			y, errLat := strconv.ParseFloat(lat, 64)
+			if errLon == nil && errLat == nil {
+				e.Address.Geolocacation.Type = "Point"
+				e.Address.Geolocacation.Coordinates = []float64{x, y}
+			}
+		}
+		if id, ok := strings.CutPrefix(a.OriginID, "nominatim:"); ok {
+			e.Address.Geolocacation.OsmID, _ = strconv.ParseInt(id, 10, 64)
+		}
+	}
+	return e
+}
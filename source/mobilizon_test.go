@@ -0,0 +1,111 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/markjaroski/go-mobilizon-bot/mobilizon"
+)
+
+// fakeSearcher pages through its events
+type fakeSearcher struct {
+	events  []mobilizon.Event
+	err     error
+	queries []mobilizon.SearchParams
+}
+
+func (f *fakeSearcher) SearchEventPage(ctx context.Context, p mobilizon.SearchParams) ([]mobilizon.Event, int, error) {
+	f.queries = append(f.queries, p)
+	if f.err != nil {
+		return nil, 0, f.err
+	}
+	start := min((p.Page-1)*p.Limit, len(f.events))
+	end := min(start+p.Limit, len(f.events))
+	return f.events[start:end], len(f.events), nil
+}
+
+func TestMobilizon(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	remoteEvent := func(n string, local bool) mobilizon.Event {
+		return mobilizon.Event{
+			URL:      "https://mobilizon.example/events/" + n,
+			Local:    local,
+			Title:    "Event " + n,
+			BeginsOn: now.Add(24 * time.Hour),
+			Status:   mobilizon.EventStatusConfirmed,
+		}
+	}
+	concert := remoteEvent("1", true)
+	concert.Category = mobilizon.EventCategoryMusic
+	concert.Tags = []string{"rock", "live"}
+	concert.Picture = &mobilizon.Picture{URL: "https://mobilizon.example/media/1.jpg"}
+	concert.PhysicalAddress = &mobilizon.Address{
+		Description: "Salle des fêtes",
+		Street:      "Rue du Lac 1",
+		PostalCode:  "1000",
+		Locality:    "Lausanne",
+		Country:     "Switzerland",
+		Geom:        "6.63;46.52",
+		OriginID:    "nominatim:1234",
+	}
+	cancelled := remoteEvent("2", true)
+	cancelled.Status = mobilizon.EventStatusCancelled
+	federated := remoteEvent("3", true)
+	elsewhere := remoteEvent("4", false)
+
+	remote := &fakeSearcher{events: []mobilizon.Event{concert, cancelled, federated, elsewhere, remoteEvent("5", true)}}
+	// our instance already has event 3, federated from the neighbour
+	local := &fakeSearcher{events: []mobilizon.Event{federated}}
+
+	s := NewMobilizon("https://mobilizon.example", "u0k8wu", 20)
+	s.Remote, s.Local = remote, local
+	s.PageSize = 2
+	s.Horizon = 30 * 24 * time.Hour
+	s.now = func() time.Time { return now }
+
+	events, err := s.Events(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0].Title != "Event 1" || events[1].Title != "Event 5" {
+		t.Fatalf("unexpected events %+v", events)
+	}
+	if len(remote.queries) != 3 {
+		t.Errorf("expected 3 pages, got %d", len(remote.queries))
+	}
+	q := remote.queries[0]
+	if q.Location != "u0k8wu" || q.Radius != 20 || !q.BeginsOn.Equal(now) || !q.EndsOn.Equal(now.Add(s.Horizon)) {
+		t.Errorf("unexpected search %+v", q)
+	}
+
+	e := events[0]
+	if e.URL != concert.URL || e.SourceURL != "https://mobilizon.example" {
+		t.Errorf("expected the original URL to be kept, got %s", e.URL)
+	}
+	if e.Type != "MUSIC" || e.GenresText != "rock, live" || e.ImageURL != concert.Picture.URL {
+		t.Errorf("unexpected event %+v", e)
+	}
+	if e.Location != "Salle des fêtes" || e.City != "Lausanne" || e.Address.PostCode != "1000" || e.Address.Geolocacation.OsmID != 1234 {
+		t.Errorf("unexpected address %+v", e)
+	}
+	if c := e.Address.Geolocacation.Coordinates; len(c) != 2 || c[0] != 6.63 || c[1] != 46.52 {
+		t.Errorf("expected lon, lat coordinates, got %v", c)
+	}
+}
+
+func TestMobilizon_Error(t *testing.T) {
+	s := NewMobilizon("https://mobilizon.example", "u0k8wu", 20)
+	s.Remote = &fakeSearcher{err: errors.New("unavailable")}
+	if _, err := s.Events(context.Background()); err == nil {
+		t.Error("expected an error when the instance can't be searched")
+	}
+
+	// our instance failing must not mirror what may already be federated
+	s.Remote = &fakeSearcher{events: []mobilizon.Event{{Local: true, Title: "x"}}}
+	s.Local = &fakeSearcher{err: errors.New("unavailable")}
+	if _, err := s.Events(context.Background()); err == nil {
+		t.Error("expected an error when our instance can't be searched")
+	}
+}
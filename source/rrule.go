@@ -0,0 +1,266 @@
+package source
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rrule is the part of an RFC 5545 recurrence rule venues actually use:
+// daily, weekly, monthly and yearly rules with INTERVAL, COUNT, UNTIL,
+// BYDAY, BYMONTHDAY and BYMONTH
+type rrule struct {
+	freq       string
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []weekdayNum
+	byMonthDay []int
+	byMonth    []time.Month
+}
+
+// weekdayNum is a BYDAY entry such as MO, 1FR or -1SU
+type weekdayNum struct {
+	n   int
+	day time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// maxPeriods stops runaway rules, such as a daily rule started decades ago
+const maxPeriods = 100000
+
+func parseRRule(rule string, tz *time.Location) (*rrule, error) {
+	r := &rrule{interval: 1}
+	for _, part := range strings.Split(rule, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			r.freq = strings.ToUpper(v)
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", v)
+			}
+			r.interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", v)
+			}
+			r.count = n
+		case "UNTIL":
+			t, allDay, err := parseICSTime(v, "", tz)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UNTIL %q", v)
+			}
+			// a date includes the whole day
+			if allDay {
+				t = t.AddDate(0, 0, 1).Add(-time.Second)
+			}
+			r.until = t
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				d = strings.ToUpper(strings.TrimSpace(d))
+				if len(d) < 2 {
+					return nil, fmt.Errorf("invalid BYDAY %q", v)
+				}
+				day, ok := weekdays[d[len(d)-2:]]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY %q", v)
+				}
+				n := 0
+				if num := d[:len(d)-2]; num != "" {
+					var err error
+					if n, err = strconv.Atoi(num); err != nil {
+						return nil, fmt.Errorf("invalid BYDAY %q", v)
+					}
+				}
+				r.byDay = append(r.byDay, weekdayNum{n, day})
+			}
+		case "BYMONTHDAY":
+			for _, d := range strings.Split(v, ",") {
+				n, err := strconv.Atoi(d)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %q", v)
+				}
+				r.byMonthDay = append(r.byMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, m := range strings.Split(v, ",") {
+				n, err := strconv.Atoi(m)
+				if err != nil || n < 1 || n > 12 {
+					return nil, fmt.Errorf("invalid BYMONTH %q", v)
+				}
+				r.byMonth = append(r.byMonth, time.Month(n))
+			}
+		case "WKST":
+		default:
+			return nil, fmt.Errorf("unsupported rule part %q", k)
+		}
+	}
+	switch r.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, fmt.Errorf("unsupported FREQ %q", r.freq)
+	}
+	return r, nil
+}
+
+// expand returns the start of every occurrence from dtstart up to end,
+// honouring COUNT and UNTIL. COUNT counts from dtstart, so occurrences
+// which are already past still count.
+func (r *rrule) expand(dtstart time.Time, end time.Time) []time.Time {
+	var starts []time.Time
+	n := 0
+	for period := 0; period < maxPeriods; period++ {
+		candidates, periodStart := r.period(dtstart, period)
+		if periodStart.After(end) || (!r.until.IsZero() && periodStart.After(r.until)) {
+			break
+		}
+		for _, t := range candidates {
+			if t.Before(dtstart) {
+				continue
+			}
+			if (!r.until.IsZero() && t.After(r.until)) || t.After(end) {
+				return starts
+			}
+			starts = append(starts, t)
+			n++
+			if r.count > 0 && n >= r.count {
+				return starts
+			}
+		}
+	}
+	return starts
+}
+
+// period returns the sorted candidate starts in the given period of the
+// rule, and the day the period starts on
+func (r *rrule) period(dtstart time.Time, period int) ([]time.Time, time.Time) {
+	y, m, d := dtstart.Date()
+	hh, mm, ss := dtstart.Clock()
+	loc := dtstart.Location()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hh, mm, ss, 0, loc)
+	}
+
+	var days []time.Time
+	var start time.Time
+	switch r.freq {
+	case "DAILY":
+		start = at(y, m, d+period*r.interval)
+		if r.matchesMonth(start.Month()) && r.matchesWeekday(start.Weekday()) {
+			days = append(days, start)
+		}
+
+	case "WEEKLY":
+		// weeks start on Monday
+		monday := at(y, m, d-(int(dtstart.Weekday())+6)%7+period*7*r.interval)
+		start = monday
+		if len(r.byDay) == 0 {
+			days = append(days, at(y, m, d+period*7*r.interval))
+			break
+		}
+		for i := 0; i < 7; i++ {
+			day := monday.AddDate(0, 0, i)
+			if r.matchesMonth(day.Month()) && r.matchesWeekday(day.Weekday()) {
+				days = append(days, day)
+			}
+		}
+
+	case "MONTHLY":
+		start = at(y, m+time.Month(period*r.interval), 1)
+		if r.matchesMonth(start.Month()) {
+			days = r.daysInMonth(start, d)
+		}
+
+	case "YEARLY":
+		start = at(y+period*r.interval, 1, 1)
+		months := r.byMonth
+		if len(months) == 0 {
+			months = []time.Month{m}
+		}
+		for _, month := range months {
+			first := at(start.Year(), month, 1)
+			if len(r.byDay) == 0 && len(r.byMonthDay) == 0 {
+				// the same date every year, skipping a missing 29 February
+				if day := at(start.Year(), month, d); day.Month() == month {
+					days = append(days, day)
+				}
+				continue
+			}
+			days = append(days, r.daysInMonth(first, d)...)
+		}
+	}
+
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(days, func(a, b time.Time) bool { return a.Equal(b) }), start
+}
+
+// daysInMonth applies BYMONTHDAY and BYDAY to the month starting at first,
+// or else repeats the day of month of dtstart
+func (r *rrule) daysInMonth(first time.Time, dtstartDay int) []time.Time {
+	month := first.Month()
+	last := first.AddDate(0, 1, -1).Day()
+	var days []time.Time
+
+	switch {
+	case len(r.byMonthDay) > 0:
+		for _, md := range r.byMonthDay {
+			if md < 0 {
+				md = last + md + 1
+			}
+			if md < 1 || md > last {
+				continue
+			}
+			day := first.AddDate(0, 0, md-1)
+			if len(r.byDay) == 0 || r.matchesWeekday(day.Weekday()) {
+				days = append(days, day)
+			}
+		}
+
+	case len(r.byDay) > 0:
+		for _, wd := range r.byDay {
+			var matches []time.Time
+			for day := first; day.Month() == month; day = day.AddDate(0, 0, 1) {
+				if day.Weekday() == wd.day {
+					matches = append(matches, day)
+				}
+			}
+			switch {
+			case wd.n == 0:
+				days = append(days, matches...)
+			case wd.n > 0 && wd.n <= len(matches):
+				days = append(days, matches[wd.n-1])
+			case wd.n < 0 && -wd.n <= len(matches):
+				days = append(days, matches[len(matches)+wd.n])
+			}
+		}
+
+	case dtstartDay <= last:
+		days = append(days, first.AddDate(0, 0, dtstartDay-1))
+	}
+	return days
+}
+
+func (r *rrule) matchesMonth(m time.Month) bool {
+	return len(r.byMonth) == 0 || slices.Contains(r.byMonth, m)
+}
+
+func (r *rrule) matchesWeekday(d time.Weekday) bool {
+	if len(r.byDay) == 0 {
+		return true
+	}
+	for _, wd := range r.byDay {
+		if wd.day == d {
+			return true
+		}
+	}
+	return false
+}
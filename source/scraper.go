@@ -0,0 +1,163 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jakopako/goskyr/scraper"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+)
+
+// DefaultScraperTimeout bounds a single goskyr scraper
+const DefaultScraperTimeout = 5 * time.Minute
+
+// Goskyr runs the scrapers of goskyr configs, such as those in
+// goskyr-config/, and reads their events. The scrapers run in-process, each
+// on its own, so one broken scraper only loses its own events.
+type Goskyr struct {
+	// Configs are goskyr YAML configs, or directories of them
+	Configs []string
+	Timeout time.Duration
+
+	// scrape runs a single scraper, goskyr's GetItems unless a test
+	// replaces it
+	scrape  func(sc scraper.Scraper, global *scraper.GlobalConfig) ([]map[string]any, error)
+	results []FeedResult
+}
+
+// NewGoskyr creates a source running the scrapers of the given configs
+func NewGoskyr(configs ...string) *Goskyr {
+	return &Goskyr{
+		Configs: configs,
+		Timeout: DefaultScraperTimeout,
+		scrape: func(sc scraper.Scraper, global *scraper.GlobalConfig) ([]map[string]any, error) {
+			return sc.GetItems(global, false)
+		},
+	}
+}
+
+// Events runs every scraper and returns the events of those which worked.
+// It only fails when no scraper worked.
+func (s *Goskyr) Events(ctx context.Context) ([]concertcloud.Event, error) {
+	files, err := s.configFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	s.results = nil
+	var events []concertcloud.Event
+	for _, f := range files {
+		conf, err := scraper.NewConfig(f)
+		if err == nil && len(conf.Scrapers) == 0 {
+			err = fmt.Errorf("goskyr config %s has no scrapers", f)
+		}
+		if err != nil {
+			s.results = append(s.results, FeedResult{Name: f, Err: err})
+			continue
+		}
+		for i, sc := range conf.Scrapers {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			name := sc.Name
+			if name == "" {
+				name = fmt.Sprintf("%s#%d", filepath.Base(f), i)
+			}
+			e, err := s.run(ctx, sc, &conf.Global)
+			s.results = append(s.results, FeedResult{Name: name, Items: len(e), Err: err})
+			events = append(events, e...)
+		}
+	}
+
+	if len(s.results) > 0 && !slices.ContainsFunc(s.results, func(r FeedResult) bool { return r.Err == nil }) {
+		return nil, fmt.Errorf("all %d scrapers failed, first: %s: %w", len(s.results), s.results[0].Name, s.results[0].Err)
+	}
+	return events, nil
+}
+
+func (s *Goskyr) String() string {
+	return "goskyr " + strings.Join(s.Configs, ",")
+}
+
+// Results reports what each scraper yielded in the last run
+func (s *Goskyr) Results() []FeedResult {
+	return s.results
+}
+
+// configFiles expands directories into the YAML files they hold
+func (s *Goskyr) configFiles() ([]string, error) {
+	var files []string
+	for _, c := range s.Configs {
+		fi, err := os.Stat(c)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			files = append(files, c)
+			continue
+		}
+		for _, pattern := range []string{"*.yml", "*.yaml"} {
+			found, err := filepath.Glob(filepath.Join(c, pattern))
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, found...)
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// run scrapes with a single scraper and decodes the items it finds. The
+// config's own writer is never used. A scraper which panics or outlives
+// the timeout fails on its own, though goskyr gives no way to stop one
+// which hangs.
+func (s *Goskyr) run(ctx context.Context, sc scraper.Scraper, global *scraper.GlobalConfig) ([]concertcloud.Event, error) {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultScraperTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type scraped struct {
+		items []map[string]any
+		err   error
+	}
+	done := make(chan scraped, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- scraped{err: fmt.Errorf("scraper panicked: %v", r)}
+			}
+		}()
+		items, err := s.scrape(sc, global)
+		done <- scraped{items, err}
+	}()
+
+	var res scraped
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res = <-done:
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+	if len(res.items) == 0 {
+		return nil, nil
+	}
+	dat, err := json.Marshal(res.items)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeEvents(bytes.NewReader(dat), "goskyr items")
+}
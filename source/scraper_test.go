@@ -0,0 +1,134 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jakopako/goskyr/scraper"
+)
+
+// testGoskyr runs the scrapers with a stand-in for goskyr: it fails for the
+// scraper named Broken, panics for the one named Panic, finds nothing for
+// the one named Empty and otherwise finds two items
+func testGoskyr(configs ...string) *Goskyr {
+	s := NewGoskyr(configs...)
+	s.scrape = func(sc scraper.Scraper, global *scraper.GlobalConfig) ([]map[string]any, error) {
+		switch sc.Name {
+		case "Broken":
+			return nil, errors.New("selector not found")
+		case "Panic":
+			panic("index out of range")
+		case "Empty":
+			return nil, nil
+		}
+		item := func(n int) map[string]any {
+			return map[string]any{"title": fmt.Sprintf("%s %d", sc.Name, n), "location": "Bad Bonn", "date": "2026-11-01T20:00:00Z"}
+		}
+		return []map[string]any{item(1), item(2)}, nil
+	}
+	return s
+}
+
+func TestGoskyr(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "venues.yml", `writer:
+  type: file
+  filepath: out.json
+scrapers:
+  - name: Select
+    url: https://example.org/select
+  - name: Broken
+    url: https://example.org/broken
+  - name: Empty
+    url: https://example.org/empty
+  - name: Panic
+    url: https://example.org/panic
+`)
+	writeFile(t, dir, "other.yaml", `scrapers:
+  - name: Other
+    url: https://example.org/other
+`)
+	writeFile(t, dir, "notes.txt", "not a config")
+
+	s := testGoskyr(dir)
+	events, err := s.Events(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var titles []string
+	for _, e := range events {
+		titles = append(titles, e.Title)
+	}
+	if got := strings.Join(titles, ","); got != "Other 1,Other 2,Select 1,Select 2" {
+		t.Errorf("unexpected events %s", got)
+	}
+
+	results := s.Results()
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %+v", results)
+	}
+	for _, r := range results {
+		switch r.Name {
+		case "Broken":
+			if r.Err == nil || !strings.Contains(r.Err.Error(), "selector not found") {
+				t.Errorf("expected the scraper's error, got %v", r.Err)
+			}
+		case "Panic":
+			if r.Err == nil || !strings.Contains(r.Err.Error(), "panicked") {
+				t.Errorf("expected the scraper's panic, got %v", r.Err)
+			}
+		case "Empty":
+			if r.Err != nil || r.Items != 0 {
+				t.Errorf("expected no items and no error, got %+v", r)
+			}
+		default:
+			if r.Err != nil || r.Items != 2 {
+				t.Errorf("unexpected result %+v", r)
+			}
+		}
+	}
+}
+
+func TestGoskyr_AllFailed(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "broken.yml", `scrapers:
+  - name: Broken
+`)
+	if _, err := testGoskyr(path).Events(context.Background()); err == nil {
+		t.Error("expected an error when every scraper fails")
+	}
+	if _, err := testGoskyr(path + ".missing").Events(context.Background()); err == nil {
+		t.Error("expected an error for a missing config")
+	}
+}
+
+func TestGoskyr_Timeout(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "slow.yml", `scrapers:
+  - name: Slow
+  - name: Select
+`)
+	s := testGoskyr(path)
+	scrape := s.scrape
+	s.scrape = func(sc scraper.Scraper, global *scraper.GlobalConfig) ([]map[string]any, error) {
+		if sc.Name == "Slow" {
+			time.Sleep(time.Second)
+		}
+		return scrape(sc, global)
+	}
+	s.Timeout = 50 * time.Millisecond
+
+	events, err := s.Events(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("expected the events of the other scraper, got %+v", events)
+	}
+	if r := s.Results()[0]; r.Name != "Slow" || !errors.Is(r.Err, context.DeadlineExceeded) {
+		t.Errorf("expected the slow scraper to time out, got %+v", r)
+	}
+}
@@ -0,0 +1,31 @@
+// Package source provides the feeds the bot mirrors events from. Whatever
+// format a source reads, it yields ConcertCloud events.
+package source
+
+import (
+	"context"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+)
+
+// Source yields the events to mirror
+type Source interface {
+	// Events fetches every event the source currently lists
+	Events(ctx context.Context) ([]concertcloud.Event, error)
+	// String describes the source for logs
+	String() string
+}
+
+// FeedResult is what one feed of a source yielded in the last run
+type FeedResult struct {
+	Name  string
+	Items int
+	Err   error
+}
+
+// Reporter is implemented by sources made of several feeds, such as a set
+// of scrapers, where one feed can fail without failing the others. The
+// events of a source with failed feeds are incomplete.
+type Reporter interface {
+	Results() []FeedResult
+}
@@ -0,0 +1,227 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+)
+
+const goskyrOutput = `[
+	{"title": "First", "location": "Bad Bonn", "city": "Düdingen", "date": "2026-11-01T20:00:00Z"},
+	{"title": "Second", "location": "Bad Bonn", "city": "Düdingen", "date": "2026-11-02T20:00:00Z"}
+]`
+
+// fakeFetcher answers every query with the same events
+type fakeFetcher struct {
+	events []concertcloud.Event
+	err    error
+	params concertcloud.QueryParams
+}
+
+func (f *fakeFetcher) GetEvents(ctx context.Context, params concertcloud.QueryParams) (*concertcloud.EventResponse, error) {
+	f.params = params
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &concertcloud.EventResponse{Data: f.events}, nil
+}
+
+func (f *fakeFetcher) AllEvents(ctx context.Context, params concertcloud.QueryParams) iter.Seq2[concertcloud.Event, error] {
+	return func(yield func(concertcloud.Event, error) bool) {
+		f.params = params
+		if f.err != nil {
+			yield(concertcloud.Event{}, f.err)
+			return
+		}
+		// every event as a page of its own
+		for _, e := range f.events {
+			if !yield(e, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (f *fakeFetcher) GetEventsByCity(ctx context.Context, city string, limit int) (*concertcloud.EventResponse, error) {
+	return f.GetEvents(ctx, concertcloud.QueryParams{City: city, Limit: limit})
+}
+
+func (f *fakeFetcher) GetEventsByCountry(ctx context.Context, country string, limit int) (*concertcloud.EventResponse, error) {
+	return f.GetEvents(ctx, concertcloud.QueryParams{Country: country, Limit: limit})
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConcertCloud(t *testing.T) {
+	f := &fakeFetcher{events: []concertcloud.Event{{Title: "First"}, {Title: "Second"}}}
+	s := NewConcertCloud(f, concertcloud.QueryParams{City: "Bern", Limit: 5})
+
+	events, err := s.Events(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[1].Title != "Second" {
+		t.Errorf("unexpected events %+v", events)
+	}
+	if f.params.City != "Bern" || f.params.Limit != 5 {
+		t.Errorf("query not passed on, got %+v", f.params)
+	}
+
+	// a single page
+	s.Params.Page = 2
+	if _, err := s.Events(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.params.Page != 2 {
+		t.Errorf("expected page 2 to be fetched, got %d", f.params.Page)
+	}
+
+	f.err = errors.New("boom")
+	if _, err := s.Events(context.Background()); err == nil {
+		t.Error("expected the fetch error")
+	}
+	s.Params.Page = 0
+	if _, err := s.Events(context.Background()); err == nil {
+		t.Error("expected the fetch error")
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "events.json", goskyrOutput)
+
+	events, err := NewFile(path).Events(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[1].Title != "Second" {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
+
+func TestFile_Invalid(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "events.json", `{"title": "not an array"}`)
+
+	if _, err := NewFile(path).Events(context.Background()); err == nil {
+		t.Error("expected an error for invalid output")
+	}
+	if _, err := NewFile(path + ".missing").Events(context.Background()); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestFile_Formats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"array", goskyrOutput},
+		{"data wrapper", `{"data": ` + goskyrOutput + `, "page": 1, "last_page": 1}`},
+		{"keyed map", `{
+			"https://badbonn.ch/1#2026-11-01": {"title": "First", "location": "Bad Bonn", "date": "2026-11-01T20:00:00Z"},
+			"https://badbonn.ch/2#2026-11-02": {"title": "Second", "location": "Bad Bonn", "date": "2026-11-02T20:00:00Z"}
+		}`},
+		{"event cache", `{
+			"https://badbonn.ch/1#2026-11-01": {"uuid": "8e7c4fc4-5d0b-4b4c-9d1c-2c1f5f4b6a01", "event": {"title": "First", "location": "Bad Bonn", "date": "2026-11-01T20:00:00Z"}},
+			"https://badbonn.ch/2#2026-11-02": {"uuid": "8e7c4fc4-5d0b-4b4c-9d1c-2c1f5f4b6a02", "event": {"title": "Second", "location": "Bad Bonn", "date": "2026-11-02T20:00:00Z"}}
+		}`},
+		{"json lines", `{"title": "First", "location": "Bad Bonn", "date": "2026-11-01T20:00:00Z"}
+
+{"title": "Second", "location": "Bad Bonn", "date": "2026-11-02T20:00:00Z"}
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, t.TempDir(), "events.json", tt.content)
+			events, err := NewFile(path).Events(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(events) != 2 || events[0].Title != "First" || events[1].Title != "Second" {
+				t.Errorf("unexpected events %+v", events)
+			}
+		})
+	}
+}
+
+func TestFile_InvalidRecords(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "events.json", `[
+		{"title": "Fine", "location": "Bad Bonn", "date": "2026-11-01T20:00:00Z", "url": "https://badbonn.ch/1", "type": "MUSIC"},
+		{"location": "Bad Bonn", "date": "2026-11-01T20:00:00Z"},
+		{"title": "No date or place"},
+		{"title": "Bad link", "location": "Bad Bonn", "date": "2026-11-01T20:00:00Z", "url": "badbonn.ch/2"},
+		{"title": "Bad type", "location": "Bad Bonn", "date": "2026-11-01T20:00:00Z", "type": "concert"},
+		{"title": "Bad date", "location": "Bad Bonn", "date": "tomorrow"}
+	]`)
+
+	events, err := NewFile(path).Events(context.Background())
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if events != nil {
+		t.Errorf("expected the file to be rejected, got %d events", len(events))
+	}
+	if verr.Total != 6 || len(verr.Records) != 5 {
+		t.Fatalf("expected 5 of 6 records invalid, got %d of %d", len(verr.Records), verr.Total)
+	}
+	want := []string{
+		"record 2: missing title",
+		"record 3: missing date, missing location",
+		`record 4: invalid url "badbonn.ch/2"`,
+		`record 5: unknown category "concert"`,
+		"record 6: ",
+	}
+	for i, w := range want {
+		if got := verr.Records[i].Error(); !strings.HasPrefix(got, w) {
+			t.Errorf("record error %d: expected %q, got %q", i, w, got)
+		}
+	}
+}
+
+func TestDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "b.json", goskyrOutput)
+	writeFile(t, dir, "a.json", `[{"title": "Zero", "location": "Bad Bonn", "date": "2026-10-31T20:00:00Z"}]`)
+	writeFile(t, dir, "notes.txt", "not events")
+
+	events, err := NewDir(dir).Events(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var titles []string
+	for _, e := range events {
+		titles = append(titles, e.Title)
+	}
+	if got := strings.Join(titles, ","); got != "Zero,First,Second" {
+		t.Errorf("expected events in file order, got %s", got)
+	}
+
+	if _, err := NewDir(filepath.Join(dir, "missing")).Events(context.Background()); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
+
+func TestStdin(t *testing.T) {
+	s := &Stdin{In: strings.NewReader(goskyrOutput)}
+
+	events, err := s.Events(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("expected 2 events, got %d", len(events))
+	}
+}
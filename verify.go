@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/markjaroski/go-mobilizon-bot/mobilizon"
+)
+
+// Drift is a single field which differs between what the bot would send
+// and what is live on Mobilizòn
+type Drift struct {
+	Field  string
+	Policy string
+	Want   string
+	Got    string
+}
+
+// verifyEvents fetches every cached future event from Mobilizòn, compares
+// it with what the bot would send for the cached source event, and repairs
+// the fields where the verify policy says the bot wins. With --noop it
+// only reports.
+func verifyEvents(ctx context.Context, jobs []JobConfig) {
+	loadExistingEvents()
+
+	jobsByName := make(map[string]JobConfig, len(jobs))
+	for _, j := range jobs {
+		jobsByName[j.Name] = j
+	}
+
+	checked, drifted, repaired, missing := 0, 0, 0, 0
+	now := time.Now()
+	for k, ev := range existing {
+		if ctx.Err() != nil {
+			Log.Info("Context cancelled: ", ctx.Err())
+			break
+		}
+		if !ev.Event.Date.After(now) {
+			continue
+		}
+		// events cached before jobs were recorded belong to no job until
+		// one sees them again
+		job, ok := jobsByName[ev.Job]
+		if !ok {
+			if ev.Job == "" {
+				Log.Info("Event belongs to no job and is not verified", "eventKey", k, "uuid", ev.UUID)
+			}
+			continue
+		}
+
+		checked++
+		live, err := mobClient.FetchEvent(ctx, ev.UUID)
+		if err != nil {
+			Log.Warn("Event not found on Mobilizòn", "eventKey", k, "uuid", ev.UUID, "error", err)
+			missing++
+			continue
+		}
+
+		want := eventParams(job, ev.Event)
+		drifts := compareEvent(want, live, runConfig.Verify)
+		if len(drifts) == 0 {
+			continue
+		}
+		drifted++
+
+		repair := false
+		for _, d := range drifts {
+			Log.Info("Drift", "eventKey", k, "uuid", ev.UUID, "field", d.Field, "policy", d.Policy, "want", d.Want, "got", d.Got)
+			repair = repair || d.Policy == POLICY_BOT
+		}
+		if !repair || *opts.NoOp {
+			continue
+		}
+
+		if err := repairEvent(ctx, want, live, runConfig.Verify); err != nil {
+			Log.Error("Error repairing event", "eventKey", k, "error", err)
+			continue
+		}
+		Log.Info("Repaired", "eventKey", k, "uuid", ev.UUID)
+		repaired++
+	}
+
+	saveMediaCache()
+	Log.Info("Verify summary", "checked", checked, "drifted", drifted, "repaired", repaired, "missing", missing)
+}
+
+// compareEvent lists the fields where the live event differs from the
+// params the bot would send
+func compareEvent(want mobilizon.EventParams, live *mobilizon.Event, policy VerifyPolicy) []Drift {
+	var drifts []Drift
+	add := func(field, policy, want, got string) {
+		drifts = append(drifts, Drift{Field: field, Policy: policy, Want: want, Got: got})
+	}
+
+	if strings.TrimSpace(want.Title) != strings.TrimSpace(live.Title) {
+		add("title", policy.Title, want.Title, live.Title)
+	}
+	if !want.BeginsOn.Equal(live.BeginsOn) || !want.EndsOn.Equal(live.EndsOn) {
+		add("dates", policy.Dates,
+			want.BeginsOn.Format(time.RFC3339)+" - "+want.EndsOn.Format(time.RFC3339),
+			live.BeginsOn.Format(time.RFC3339)+" - "+live.EndsOn.Format(time.RFC3339))
+	}
+	if w, g := wantAddress(want.PhysicalAddress), liveAddress(live.PhysicalAddress); w != g {
+		add("address", policy.Address, w, g)
+	}
+	if !samePicture(want.ImageURL, live.Picture) {
+		got := ""
+		if live.Picture != nil {
+			got = live.Picture.URL
+		}
+		add("picture", policy.Picture, want.ImageURL, got)
+	}
+	if w, g := wantTags(want.Tags), normalizeTags(live.Tags); !slices.Equal(w, g) {
+		add("tags", policy.Tags, strings.Join(w, ", "), strings.Join(g, ", "))
+	}
+	if want.Status != live.Status {
+		add("status", policy.Status, string(want.Status), string(live.Status))
+	}
+	return drifts
+}
+
+// repairEvent sends the bot's version of the event, keeping the live value
+// of every field where the human edit wins
+func repairEvent(ctx context.Context, want mobilizon.EventParams, live *mobilizon.Event, policy VerifyPolicy) error {
+	params := want
+	params.UUID = &live.UUID
+	if policy.Title == POLICY_HUMAN {
+		params.Title = live.Title
+	}
+	if policy.Dates == POLICY_HUMAN {
+		params.BeginsOn = live.BeginsOn
+		params.EndsOn = live.EndsOn
+	}
+	if policy.Address == POLICY_HUMAN && live.PhysicalAddress != nil {
+		params.PhysicalAddress = addressInputFromAddress(live.PhysicalAddress)
+	}
+	// never upload the picture again when it is already there
+	if live.Picture != nil && (policy.Picture == POLICY_HUMAN || samePicture(want.ImageURL, live.Picture)) {
+		params.MediaUUID = &live.Picture.UUID
+	} else if policy.Picture == POLICY_HUMAN {
+		params.ImageURL = ""
+	}
+	if policy.Tags == POLICY_HUMAN {
+		params.Tags = nil
+		for _, t := range live.Tags {
+			params.Tags = append(params.Tags, &t)
+		}
+	}
+	if policy.Status == POLICY_HUMAN {
+		params.Status = live.Status
+	}
+	_, err := mobClient.UpdateEvent(ctx, params)
+	return err
+}
+
+// samePicture reports whether the live picture is the one uploaded for the
+// source image. A picture replaced at the source, or swapped on Mobilizòn,
+// is not.
+func samePicture(imageURL string, live *mobilizon.Picture) bool {
+	if imageURL == "" || live == nil {
+		return imageURL == "" && live == nil
+	}
+	uploaded, ok := mediaCache.Picture(imageURL)
+	return ok && uploaded.UUID == live.UUID
+}
+
+// wantAddress and liveAddress render the compared address fields the same
+// way for both sides
+func wantAddress(a mobilizon.AddressInput) string {
+	return formatAddress(deref(a.Description), deref(a.Street), deref(a.PostalCode), deref(a.Locality), deref(a.Country))
+}
+
+func liveAddress(a *mobilizon.Address) string {
+	if a == nil {
+		return ""
+	}
+	return formatAddress(a.Description, a.Street, a.PostalCode, a.Locality, a.Country)
+}
+
+func formatAddress(parts ...string) string {
+	for i, p := range parts {
+		parts[i] = strings.TrimSpace(p)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// addressInputFromAddress turns a live address back into input
+func addressInputFromAddress(a *mobilizon.Address) mobilizon.AddressInput {
+	return mobilizon.AddressInput{
+		Geom:        &a.Geom,
+		Street:      &a.Street,
+		Locality:    &a.Locality,
+		PostalCode:  &a.PostalCode,
+		Region:      &a.Region,
+		Country:     &a.Country,
+		Description: &a.Description,
+		OriginId:    &a.OriginID,
+	}
+}
+
+func wantTags(tags []*string) []string {
+	var t []string
+	for _, tag := range tags {
+		if tag != nil {
+			t = append(t, *tag)
+		}
+	}
+	return normalizeTags(t)
+}
+
+// normalizeTags sorts and lower-cases tags, as Mobilizòn may change their
+// case and order
+func normalizeTags(tags []string) []string {
+	t := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			t = append(t, tag)
+		}
+	}
+	slices.Sort(t)
+	return slices.Compact(t)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
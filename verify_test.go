@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+	"github.com/markjaroski/go-mobilizon-bot/mobilizon"
+)
+
+// verifiedEvent returns the params the bot would send for a source event,
+// and the live event as Mobilizòn returns them, unchanged. The live picture
+// is the one the media cache remembers uploading for the image.
+func verifiedEvent(t *testing.T, imageURL string) (mobilizon.EventParams, *mobilizon.Event) {
+	e := concertcloud.Event{
+		Title:    "Erika Stucky",
+		City:     "Bern",
+		Location: "Dampfzentrale",
+		Date:     time.Date(2031, 5, 1, 19, 0, 0, 0, time.UTC),
+		URL:      "https://dampfzentrale.ch/erika-stucky",
+		ImageURL: imageURL,
+	}
+	e.Address.Street = "Marzilistrasse"
+	e.Address.HouseNumber = "47"
+	e.Address.PostCode = "3005"
+	e.Address.Locality = "Bern"
+	e.Address.Country = "Switzerland"
+	e.Address.Geolocacation.Coordinates = []float64{7.4436, 46.9426}
+	want := eventParams(defaultJob("bern"), e)
+
+	live := &mobilizon.Event{
+		UUID:     uuid.New(),
+		Title:    " Erika Stucky\n",
+		BeginsOn: want.BeginsOn,
+		EndsOn:   want.EndsOn,
+		PhysicalAddress: &mobilizon.Address{
+			Description: "Dampfzentrale",
+			Street:      "47 Marzilistrasse",
+			PostalCode:  "3005",
+			Locality:    "Bern",
+			Country:     "Switzerland",
+		},
+		// Mobilizòn sorts tags and may change their case
+		Tags:   []string{"bern", "DampfZentrale"},
+		Status: mobilizon.EventStatusConfirmed,
+	}
+	if imageURL != "" {
+		live.Picture = &mobilizon.Picture{UUID: uuid.New(), URL: "https://mobilisons.ch/media/1.jpg"}
+	}
+	useMediaCache(t)
+	if live.Picture != nil {
+		mediaCache.URLs[imageURL] = mobilizon.MediaSource{Hash: "erika"}
+		mediaCache.Media["erika"] = mobilizon.MediaEntry{UUID: live.Picture.UUID, Hash: "erika"}
+	}
+	return want, live
+}
+
+// useMediaCache replaces the media cache with an empty one for the test
+func useMediaCache(t *testing.T) {
+	t.Helper()
+	saved := mediaCache
+	mediaCache = mobilizon.NewMediaCache()
+	t.Cleanup(func() { mediaCache = saved })
+}
+
+func TestCompareEvent(t *testing.T) {
+	policy := defaultVerifyPolicy()
+	tests := []struct {
+		name  string
+		edit  func(live *mobilizon.Event)
+		field string
+		want  string
+	}{
+		{name: "whitespace, tag case and order are no drift"},
+		{"title", func(l *mobilizon.Event) { l.Title = "Erika Stucky (sold out)" }, "title", POLICY_HUMAN},
+		{"dates", func(l *mobilizon.Event) { l.BeginsOn = l.BeginsOn.Add(time.Hour) }, "dates", POLICY_BOT},
+		{"address", func(l *mobilizon.Event) { l.PhysicalAddress.Street = "Marzilistrasse 47" }, "address", POLICY_BOT},
+		{"picture removed", func(l *mobilizon.Event) { l.Picture = nil }, "picture", POLICY_BOT},
+		{"picture swapped", func(l *mobilizon.Event) { l.Picture.UUID = uuid.New() }, "picture", POLICY_BOT},
+		{"tags", func(l *mobilizon.Event) { l.Tags = []string{"bern"} }, "tags", POLICY_BOT},
+		{"status", func(l *mobilizon.Event) { l.Status = mobilizon.EventStatusCancelled }, "status", POLICY_HUMAN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, live := verifiedEvent(t, "https://dampfzentrale.ch/erika.jpg")
+			if tt.edit != nil {
+				tt.edit(live)
+			}
+			drifts := compareEvent(want, live, policy)
+			if tt.field == "" {
+				if len(drifts) != 0 {
+					t.Errorf("expected no drift, got %+v", drifts)
+				}
+				return
+			}
+			if len(drifts) != 1 || drifts[0].Field != tt.field || drifts[0].Policy != tt.want {
+				t.Errorf("expected drift in %s under policy %s, got %+v", tt.field, tt.want, drifts)
+			}
+		})
+	}
+}
+
+// updatingMobilizon answers the operations UpdateEvent sends, and returns
+// the variables of the update
+func updatingMobilizon(t *testing.T) func() map[string]any {
+	calls := fakeMobilizon(t, map[string]func(vars map[string]any) string{
+		"FetchEvent": func(vars map[string]any) string {
+			return `{"data": {"event": {"id": "7", "uuid": "` + vars["uuid"].(string) + `", "__typename": "Event"}}}`
+		},
+		"UpdateEvent": func(vars map[string]any) string {
+			return `{"data": {"updateEvent": {"id": "7", "uuid": "` + uuid.NewString() + `"}}}`
+		},
+	})
+	return func() map[string]any {
+		for _, c := range calls() {
+			if c.Operation == "UpdateEvent" {
+				return c.Vars
+			}
+		}
+		t.Fatal("expected the event to be updated")
+		return nil
+	}
+}
+
+func TestRepairEvent_Policy(t *testing.T) {
+	// the live event differs from what the bot would send in every field
+	edited := func() (mobilizon.EventParams, *mobilizon.Event) {
+		want, live := verifiedEvent(t, "")
+		live.Title = "Erika Stucky (sold out)"
+		live.BeginsOn, live.EndsOn = live.BeginsOn.Add(time.Hour), live.EndsOn.Add(time.Hour)
+		live.PhysicalAddress.Description = "Dampfzentrale, Turbinensaal"
+		live.Tags = []string{"jazz"}
+		live.Status = mobilizon.EventStatusTentative
+		return want, live
+	}
+	want, live := edited()
+	address := func(v map[string]any) any { return v["physicalAddress"].(map[string]any)["description"] }
+	tags := func(v map[string]any) any { return v["tags"] }
+
+	tests := []struct {
+		field string
+		set   func(p *VerifyPolicy, value string)
+		get   func(vars map[string]any) any
+		bot   any
+		human any
+	}{
+		{"title", func(p *VerifyPolicy, v string) { p.Title = v }, func(v map[string]any) any { return v["title"] }, want.Title, live.Title},
+		{"dates", func(p *VerifyPolicy, v string) { p.Dates = v }, func(v map[string]any) any { return v["beginsOn"] },
+			want.BeginsOn.Format(time.RFC3339), live.BeginsOn.Format(time.RFC3339)},
+		{"address", func(p *VerifyPolicy, v string) { p.Address = v }, address, "Dampfzentrale", live.PhysicalAddress.Description},
+		{"tags", func(p *VerifyPolicy, v string) { p.Tags = v }, tags, []any{"Dampfzentrale", "Bern"}, []any{"jazz"}},
+		{"status", func(p *VerifyPolicy, v string) { p.Status = v }, func(v map[string]any) any { return v["status"] },
+			string(want.Status), string(live.Status)},
+	}
+	for _, tt := range tests {
+		for _, policy := range []string{POLICY_BOT, POLICY_HUMAN} {
+			t.Run(tt.field+" "+policy, func(t *testing.T) {
+				vars := updatingMobilizon(t)
+				p := VerifyPolicy{POLICY_BOT, POLICY_BOT, POLICY_BOT, POLICY_BOT, POLICY_BOT, POLICY_BOT}
+				tt.set(&p, policy)
+				want, live := edited()
+				if err := repairEvent(context.Background(), want, live, p); err != nil {
+					t.Fatal(err)
+				}
+				expected := tt.bot
+				if policy == POLICY_HUMAN {
+					expected = tt.human
+				}
+				got := tt.get(vars())
+				if s, ok := got.(string); ok && tt.field == "dates" {
+					parsed, _ := time.Parse(time.RFC3339, s)
+					got = parsed.UTC().Format(time.RFC3339)
+				}
+				if !equalVar(got, expected) {
+					t.Errorf("expected %s %v, got %v", tt.field, expected, got)
+				}
+			})
+		}
+	}
+}
+
+func equalVar(got any, want any) bool {
+	if g, ok := got.([]any); ok {
+		w, ok := want.([]any)
+		return ok && slices.Equal(g, w)
+	}
+	return got == want
+}
+
+func TestRepairEvent_Picture(t *testing.T) {
+	var downloads atomic.Int32
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(images.Close)
+
+	for _, policy := range []string{POLICY_BOT, POLICY_HUMAN} {
+		t.Run("live picture "+policy, func(t *testing.T) {
+			vars := updatingMobilizon(t)
+			want, live := verifiedEvent(t, images.URL+"/erika.jpg")
+			p := defaultVerifyPolicy()
+			p.Picture = policy
+			if err := repairEvent(context.Background(), want, live, p); err != nil {
+				t.Fatal(err)
+			}
+			picture, _ := vars()["picture"].(map[string]any)
+			if picture == nil || picture["mediaUuid"] != live.Picture.UUID.String() {
+				t.Errorf("expected the live picture %s to be reused, got %v", live.Picture.UUID, picture)
+			}
+		})
+	}
+	t.Run("no live picture human", func(t *testing.T) {
+		vars := updatingMobilizon(t)
+		want, live := verifiedEvent(t, images.URL+"/erika.jpg")
+		live.Picture = nil
+		p := defaultVerifyPolicy()
+		p.Picture = POLICY_HUMAN
+		if err := repairEvent(context.Background(), want, live, p); err != nil {
+			t.Fatal(err)
+		}
+		if picture := vars()["picture"]; picture != nil {
+			t.Errorf("expected no picture to be sent, got %v", picture)
+		}
+	})
+	if n := downloads.Load(); n != 0 {
+		t.Errorf("expected no picture to be downloaded for upload, got %d downloads", n)
+	}
+
+	t.Run("swapped picture bot", func(t *testing.T) {
+		vars := updatingMobilizon(t)
+		want, live := verifiedEvent(t, images.URL+"/erika.jpg")
+		live.Picture.UUID = uuid.New()
+		if err := repairEvent(context.Background(), want, live, defaultVerifyPolicy()); err != nil {
+			t.Fatal(err)
+		}
+		if n := downloads.Load(); n != 1 {
+			t.Errorf("expected the source picture to be downloaded for upload, got %d downloads", n)
+		}
+		if picture, _ := vars()["picture"].(map[string]any); picture != nil && picture["mediaUuid"] == live.Picture.UUID.String() {
+			t.Errorf("expected the swapped picture %s to be replaced", live.Picture.UUID)
+		}
+	})
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+	"github.com/markjaroski/go-mobilizon-bot/source"
+)
+
+const WEBHOOK_CLIENTS_FILE = "webhook_clients.json"
+
+// how many pushes may wait for the worker before clients are told to retry
+const WEBHOOK_QUEUE_SIZE = 32
+
+// the largest payload we accept
+const WEBHOOK_MAX_BODY = 10 << 20
+
+// WebhookClient is a venue allowed to push its events to us. Only the hash
+// of its API key is stored.
+type WebhookClient struct {
+	Name    string    `json:"name"`
+	KeyHash string    `json:"keyHash"`
+	Job     string    `json:"job"`
+	Created time.Time `json:"created"`
+}
+
+// WebhookClients is the persisted list of webhook clients
+type WebhookClients struct {
+	Clients []WebhookClient `json:"clients"`
+}
+
+// loadWebhookClients reads the client list. A missing file gives an empty
+// list.
+func loadWebhookClients(path string) (*WebhookClients, error) {
+	dat, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &WebhookClients{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var c WebhookClients
+	if err := json.Unmarshal(dat, &c); err != nil {
+		return nil, fmt.Errorf("invalid webhook clients file %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// save writes the client list back to disk
+func (c *WebhookClients) save(path string) error {
+	Log.Debug("Saving webhook clients", "file", path)
+	data, err := json.MarshalIndent(c, "", " ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Add creates a client and returns its API key, which is not stored and so
+// can only be shown now
+func (c *WebhookClients) Add(name string, job string) (string, error) {
+	if slices.ContainsFunc(c.Clients, func(w WebhookClient) bool { return w.Name == name }) {
+		return "", fmt.Errorf("webhook client %q already exists", name)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	key := hex.EncodeToString(secret)
+	c.Clients = append(c.Clients, WebhookClient{
+		Name:    name,
+		KeyHash: hashKey(key),
+		Job:     job,
+		Created: time.Now(),
+	})
+	return key, nil
+}
+
+// Remove deletes a client, reporting whether it existed
+func (c *WebhookClients) Remove(name string) bool {
+	n := len(c.Clients)
+	c.Clients = slices.DeleteFunc(c.Clients, func(w WebhookClient) bool { return w.Name == name })
+	return len(c.Clients) < n
+}
+
+// lookup finds the client an API key belongs to
+func (c *WebhookClients) lookup(key string) *WebhookClient {
+	hash := hashKey(key)
+	for i := range c.Clients {
+		if subtle.ConstantTimeCompare([]byte(c.Clients[i].KeyHash), []byte(hash)) == 1 {
+			return &c.Clients[i]
+		}
+	}
+	return nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// push is a batch of events a client sent, waiting for the worker
+type push struct {
+	job    JobConfig
+	events []concertcloud.Event
+	done   chan pushResult
+}
+
+// pushResult is what the worker did with a push
+type pushResult struct {
+	summary JobSummary
+	uuids   []*uuid.UUID
+}
+
+// PushedEvent is the answer for one pushed event. UUID is the Mobilizòn
+// event it is mirrored as, if it is.
+type PushedEvent struct {
+	Key  string     `json:"key"`
+	UUID *uuid.UUID `json:"uuid,omitempty"`
+}
+
+// PushResponse is the answer to a push
+type PushResponse struct {
+	Events     []PushedEvent `json:"events"`
+	Created    int           `json:"created"`
+	Updated    int           `json:"updated"`
+	Unchanged  int           `json:"unchanged"`
+	Skipped    int           `json:"skipped"`
+	Duplicates int           `json:"duplicates"`
+	Failed     int           `json:"failed"`
+}
+
+// PushError is the answer to a push which was not accepted
+type PushError struct {
+	Error   string   `json:"error"`
+	Invalid []string `json:"invalid,omitempty"`
+}
+
+// webhookServer takes the events venues push to us and mirrors them, one
+// push at a time, as createEvents shares the event cache between calls
+type webhookServer struct {
+	clients *WebhookClients
+	jobs    map[string]JobConfig
+	queue   chan push
+	// mirror publishes a push, mirrorPush unless a test replaces it
+	mirror func(ctx context.Context, job JobConfig, events []concertcloud.Event) pushResult
+}
+
+func newWebhookServer(clients *WebhookClients, jobs []JobConfig) (*webhookServer, error) {
+	s := &webhookServer{
+		clients: clients,
+		jobs:    make(map[string]JobConfig),
+		queue:   make(chan push, WEBHOOK_QUEUE_SIZE),
+		mirror:  mirrorPush,
+	}
+	for _, j := range jobs {
+		s.jobs[j.Name] = j
+	}
+	for _, c := range clients.Clients {
+		if _, ok := s.jobs[c.Job]; !ok {
+			return nil, fmt.Errorf("webhook client %q: job %q not found", c.Name, c.Job)
+		}
+	}
+	return s, nil
+}
+
+func (s *webhookServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /events", s.handleEvents)
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
+
+// handleEvents accepts a single event, a list of events or any other shape
+// a local file may have, authenticated with a client's API key
+func (s *webhookServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	client := s.clients.lookup(strings.TrimSpace(key))
+	if !ok || client == nil {
+		writeJSON(w, http.StatusUnauthorized, PushError{Error: "invalid API key"})
+		return
+	}
+
+	events, err := source.DecodeEvents(http.MaxBytesReader(w, r.Body, WEBHOOK_MAX_BODY), client.Name)
+	var verr *source.ValidationError
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		writeJSON(w, http.StatusRequestEntityTooLarge, PushError{Error: fmt.Sprintf("payload larger than %d bytes", tooLarge.Limit)})
+		return
+	case errors.As(err, &verr):
+		resp := PushError{Error: fmt.Sprintf("%d of %d events invalid", len(verr.Records), verr.Total)}
+		for _, rec := range verr.Records {
+			resp.Invalid = append(resp.Invalid, rec.Error())
+		}
+		Log.Info("Rejected push", "client", client.Name, "invalid", len(verr.Records))
+		writeJSON(w, http.StatusUnprocessableEntity, resp)
+		return
+	case err != nil:
+		writeJSON(w, http.StatusBadRequest, PushError{Error: err.Error()})
+		return
+	}
+
+	p := push{job: s.jobs[client.Job], events: events, done: make(chan pushResult, 1)}
+	select {
+	case s.queue <- p:
+	default:
+		w.Header().Set("Retry-After", "60")
+		writeJSON(w, http.StatusServiceUnavailable, PushError{Error: "too many pushes waiting, try again later"})
+		return
+	}
+	Log.Info("Queued push", "client", client.Name, "job", client.Job, "events", len(events))
+
+	select {
+	case res := <-p.done:
+		resp := PushResponse{
+			Created:    res.summary.Created,
+			Updated:    res.summary.Updated,
+			Unchanged:  res.summary.Unchanged,
+			Skipped:    res.summary.Skipped,
+			Duplicates: res.summary.Duplicates,
+			Failed:     res.summary.Failed,
+		}
+		for i, e := range events {
+			resp.Events = append(resp.Events, PushedEvent{Key: eventKey(e), UUID: res.uuids[i]})
+		}
+		writeJSON(w, http.StatusOK, resp)
+	case <-r.Context().Done():
+		// the push is still mirrored, the client just doesn't hear about it
+	}
+}
+
+// work mirrors the queued pushes one at a time until the context ends
+func (s *webhookServer) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case p := <-s.queue:
+			p.done <- s.mirror(ctx, p.job, p.events)
+		}
+	}
+}
+
+// mirrorPush runs pushed events through createEvents and saves the cache
+// straight away, so that the next push sees them as cached and updates
+// them
+func mirrorPush(ctx context.Context, job JobConfig, events []concertcloud.Event) pushResult {
+	created = make(map[string]ExistingEvent)
+	summary := createEvents(ctx, job, events)
+	logSummaries([]JobSummary{summary})
+
+	res := pushResult{summary: summary, uuids: make([]*uuid.UUID, len(events))}
+	for i, e := range events {
+		if ev, ok := created[eventKey(e)]; ok {
+			id := ev.UUID
+			res.uuids[i] = &id
+		}
+	}
+
+	maps.Copy(existing, created)
+	created = existing
+	saveExistingEvents()
+	saveMediaCache()
+	return res
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		Log.Debug("Error writing response", "error", err)
+	}
+}
+
+// serve listens for pushed events until the context ends
+func serve(ctx context.Context, addr string, clients *WebhookClients) error {
+	if err := resolveJobIdentities(ctx, runConfig.Jobs); err != nil {
+		return err
+	}
+	ws, err := newWebhookServer(clients, runConfig.Jobs)
+	if err != nil {
+		return err
+	}
+	if len(clients.Clients) == 0 {
+		Log.Warn("No webhook clients configured, every push will be refused")
+	}
+
+	connect(ctx)
+	var pushed []JobConfig
+	for _, c := range clients.Clients {
+		pushed = append(pushed, ws.jobs[c.Job])
+	}
+	if err := preflight(ctx, pushed); err != nil {
+		return err
+	}
+	loadExistingEvents()
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           ws.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go ws.work(ctx)
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+
+	Log.Info("Listening for pushed events", "addr", addr, "clients", len(clients.Clients))
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
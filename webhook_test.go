@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/markjaroski/go-mobilizon-bot/concertcloud"
+)
+
+func TestWebhookClients(t *testing.T) {
+	path := filepath.Join(t.TempDir(), WEBHOOK_CLIENTS_FILE)
+	clients, err := loadWebhookClients(path)
+	if err != nil || len(clients.Clients) != 0 {
+		t.Fatalf("expected no clients without a file, got %+v, %v", clients, err)
+	}
+
+	key, err := clients.Add("badbonn", "badbonn-push")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := clients.Add("bejazz", "bejazz-push")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key == other || len(key) != 64 {
+		t.Errorf("expected distinct random keys, got %q and %q", key, other)
+	}
+	if _, err := clients.Add("badbonn", "other"); err == nil {
+		t.Error("expected a duplicate name to be refused")
+	}
+
+	if err := clients.save(path); err != nil {
+		t.Fatal(err)
+	}
+	dat, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(dat), key) || !strings.Contains(string(dat), hashKey(key)) {
+		t.Errorf("expected only the hash of the key to be saved, got %s", dat)
+	}
+
+	loaded, err := loadWebhookClients(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := loaded.lookup(key); c == nil || c.Name != "badbonn" || c.Job != "badbonn-push" {
+		t.Errorf("expected the key to belong to badbonn, got %+v", c)
+	}
+	for _, wrong := range []string{"", hashKey(key), key[:63], strings.ToUpper(key)} {
+		if c := loaded.lookup(wrong); c != nil {
+			t.Errorf("expected no client for %q, got %+v", wrong, c)
+		}
+	}
+
+	if !loaded.Remove("badbonn") || loaded.Remove("badbonn") {
+		t.Error("expected badbonn to be removed once")
+	}
+	if loaded.lookup(key) != nil || loaded.lookup(other) == nil {
+		t.Error("expected only the removed client's key to stop working")
+	}
+}
+
+func TestNewWebhookServer_UnknownJob(t *testing.T) {
+	clients := &WebhookClients{}
+	clients.Add("badbonn", "missing")
+	if _, err := newWebhookServer(clients, []JobConfig{defaultJob("badbonn-push")}); err == nil {
+		t.Error("expected an error for a client of an unknown job")
+	}
+}
+
+// fakeMirror stands in for mirrorPush, answering with a UUID for every
+// event but those titled "skipped"
+type fakeMirror struct {
+	mu     sync.Mutex
+	pushes []push
+}
+
+func (m *fakeMirror) mirror(ctx context.Context, job JobConfig, events []concertcloud.Event) pushResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pushes = append(m.pushes, push{job: job, events: events})
+	res := pushResult{summary: JobSummary{Job: job.Name}, uuids: make([]*uuid.UUID, len(events))}
+	for i, e := range events {
+		if e.Title == "skipped" {
+			res.summary.Skipped++
+			continue
+		}
+		id := uuid.NewSHA1(uuid.NameSpaceURL, []byte(eventKey(e)))
+		res.uuids[i] = &id
+		res.summary.Created++
+	}
+	return res
+}
+
+func (m *fakeMirror) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.pushes)
+}
+
+// testWebhookServer starts the worker of a server with one client, whose
+// key it returns
+func testWebhookServer(t *testing.T) (*webhookServer, *fakeMirror, string) {
+	t.Helper()
+	clients := &WebhookClients{}
+	key, err := clients.Add("badbonn", "badbonn-push")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := newWebhookServer(clients, []JobConfig{defaultJob("zurich"), defaultJob("badbonn-push")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &fakeMirror{}
+	s.mirror = m.mirror
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go s.work(ctx)
+	return s, m, key
+}
+
+func pushEvents(s *webhookServer, auth string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, req)
+	return rec
+}
+
+// pushedEvent is a valid event on the given day of November 2031
+func pushedEvent(title string, day int) string {
+	return fmt.Sprintf(`{"title": %q, "location": "Bad Bonn", "city": "Düdingen", "date": "2031-11-%02dT20:00:00Z"}`, title, day)
+}
+
+func TestHandleEvents_Unauthorized(t *testing.T) {
+	s, m, key := testWebhookServer(t)
+	for _, auth := range []string{"", "Bearer ", "Bearer wrong", "Basic " + key, key, "Bearer " + hashKey(key)} {
+		rec := pushEvents(s, auth, `[]`)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%q: expected 401, got %d", auth, rec.Code)
+		}
+	}
+	if m.count() != 0 {
+		t.Error("expected nothing to be mirrored")
+	}
+}
+
+func TestHandleEvents_Invalid(t *testing.T) {
+	s, m, key := testWebhookServer(t)
+	body := `[` + pushedEvent("Kety Fusco", 1) + `, {"location": "Bad Bonn"}]`
+	rec := pushEvents(s, "Bearer "+key, body)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rec.Code, rec.Body)
+	}
+	var resp PushError
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Invalid) != 1 || !strings.Contains(resp.Invalid[0], "missing title") || !strings.Contains(resp.Invalid[0], "missing date") {
+		t.Errorf("expected the second record to be listed, got %+v", resp)
+	}
+	if m.count() != 0 {
+		t.Error("expected a push with invalid events not to be mirrored at all")
+	}
+
+	if rec := pushEvents(s, "Bearer "+key, `not json`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a body which isn't JSON, got %d", rec.Code)
+	}
+}
+
+func TestHandleEvents_TooLarge(t *testing.T) {
+	s, m, key := testWebhookServer(t)
+	body := `[` + strings.Repeat(" ", WEBHOOK_MAX_BODY) + `]`
+	rec := pushEvents(s, "Bearer "+key, body)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d: %s", rec.Code, rec.Body)
+	}
+	if m.count() != 0 {
+		t.Error("expected nothing to be mirrored")
+	}
+}
+
+func TestHandleEvents_QueueFull(t *testing.T) {
+	s, m, key := testWebhookServer(t)
+	// a queue nobody reads from is always full
+	s.queue = make(chan push)
+	rec := pushEvents(s, "Bearer "+key, `[]`)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+	if m.count() != 0 {
+		t.Error("expected nothing to be mirrored")
+	}
+}
+
+func TestHandleEvents_Push(t *testing.T) {
+	s, m, key := testWebhookServer(t)
+	var records []string
+	var events []concertcloud.Event
+	for i, title := range []string{"Kety Fusco", "skipped", "Hermanos Gutiérrez"} {
+		rec := pushedEvent(title, i+1)
+		records = append(records, rec)
+		var e concertcloud.Event
+		if err := json.Unmarshal([]byte(rec), &e); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, e)
+	}
+
+	rec := pushEvents(s, "Bearer "+key, "["+strings.Join(records, ",")+"]")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var resp PushResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Created != 2 || resp.Skipped != 1 || len(resp.Events) != 3 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	for i, e := range events {
+		got := resp.Events[i]
+		if got.Key != eventKey(e) {
+			t.Errorf("event %d: expected key %s, got %s", i, eventKey(e), got.Key)
+		}
+		switch {
+		case e.Title == "skipped" && got.UUID != nil:
+			t.Errorf("event %d: expected no UUID, got %s", i, got.UUID)
+		case e.Title != "skipped" && (got.UUID == nil || *got.UUID != uuid.NewSHA1(uuid.NameSpaceURL, []byte(eventKey(e)))):
+			t.Errorf("event %d: expected the UUID it was mirrored as, got %v", i, got.UUID)
+		}
+	}
+
+	if m.count() != 1 || m.pushes[0].job.Name != "badbonn-push" || len(m.pushes[0].events) != 3 {
+		t.Errorf("expected one push mirrored with the client's job, got %+v", m.pushes)
+	}
+}